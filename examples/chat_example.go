@@ -66,7 +66,7 @@ func simpleTextExample(client *chat.Client) {
 	fmt.Printf("Chat ID: %s\n", response.ID)
 	fmt.Printf("Input: %v\n", response.Input)
 	if len(response.Output) > 0 {
-		fmt.Printf("Assistant Response: %s\n", response.Output[0].Message)
+		fmt.Printf("Assistant Response: %s\n", response.Output[0].Content)
 	}
 	fmt.Printf("Cost: $%.4f\n", response.Cost)
 }
@@ -88,7 +88,7 @@ func customAssistantExample(client *chat.Client) {
 	fmt.Printf("Chat ID: %s\n", response.ID)
 	fmt.Printf("Assistant Name: %s\n", *response.Assistant.Name)
 	if len(response.Output) > 0 {
-		fmt.Printf("Assistant Response: %s\n", response.Output[0].Message)
+		fmt.Printf("Assistant Response: %s\n", response.Output[0].Content)
 	}
 }
 
@@ -150,7 +150,7 @@ func conversationExample(client *chat.Client) {
 	fmt.Printf("Chat ID: %s\n", response.ID)
 	fmt.Printf("Message History Length: %d\n", len(response.Messages))
 	if len(response.Output) > 0 {
-		fmt.Printf("Assistant Response: %s\n", response.Output[0].Message)
+		fmt.Printf("Assistant Response: %s\n", response.Output[0].Content)
 	}
 }
 
@@ -179,7 +179,7 @@ func telecomSalesExample(client *chat.Client) {
 	fmt.Printf("Chat Name: %s\n", *response.Name)
 	fmt.Printf("Assistant: %s\n", *response.Assistant.Name)
 	if len(response.Output) > 0 {
-		fmt.Printf("Respuesta del Asistente: %s\n", response.Output[0].Message)
+		fmt.Printf("Respuesta del Asistente: %s\n", response.Output[0].Content)
 	}
 }
 
@@ -198,7 +198,7 @@ func continueChatExample(client *chat.Client) {
 
 	fmt.Printf("Initial Chat ID: %s\n", initialResponse.ID)
 	if len(initialResponse.Output) > 0 {
-		fmt.Printf("Initial Response: %s\n", initialResponse.Output[0].Message)
+		fmt.Printf("Initial Response: %s\n", initialResponse.Output[0].Content)
 	}
 
 	// Continue the conversation
@@ -211,7 +211,7 @@ func continueChatExample(client *chat.Client) {
 	fmt.Printf("Continuation Chat ID: %s\n", continuationResponse.ID)
 	fmt.Printf("Previous Chat ID: %s\n", *continuationResponse.PreviousChatID)
 	if len(continuationResponse.Output) > 0 {
-		fmt.Printf("Continuation Response: %s\n", continuationResponse.Output[0].Message)
+		fmt.Printf("Continuation Response: %s\n", continuationResponse.Output[0].Content)
 	}
 }
 
@@ -92,7 +92,7 @@ func main() {
 	if err != nil {
 		log.Printf("Chat failed: %v", err)
 	} else {
-		log.Printf("Chat response: %s", chatResp.Message)
+		log.Printf("Chat response: %s", chatResp.Text())
 	}
 
 	// Keep the server running
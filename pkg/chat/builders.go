@@ -2,11 +2,15 @@ package chat
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // AssistantBuilder helps build Assistant configurations
 type AssistantBuilder struct {
 	assistant *Assistant
+	// err is the first error of a builder method, returned by Validate
+	err error
 }
 
 // NewAssistantBuilder creates a new AssistantBuilder
@@ -66,20 +70,89 @@ func (b *AssistantBuilder) WithAssistantMessage(content string) *AssistantBuilde
 
 // WithVoice sets the voice configuration
 func (b *AssistantBuilder) WithVoice(provider, voiceID string) *AssistantBuilder {
-	b.assistant.Voice = &Voice{
-		Provider: provider,
-		VoiceID:  voiceID,
+	if b.assistant.Voice == nil {
+		b.assistant.Voice = &Voice{}
+	}
+	b.assistant.Voice.Provider = provider
+	b.assistant.Voice.VoiceID = voiceID
+	return b
+}
+
+// WithChunkPlan enables or disables TTS chunking and sets the minimum characters per chunk.
+// A minCharacters of zero keeps VAPI's default.
+func (b *AssistantBuilder) WithChunkPlan(enabled bool, minCharacters int) *AssistantBuilder {
+	chunkPlan := b.chunkPlan()
+	chunkPlan.Enabled = &enabled
+	if minCharacters > 0 {
+		chunkPlan.MinCharacters = &minCharacters
 	}
 	return b
 }
 
+// WithNumberToDigitsCutoff reads numbers greater than cutoff digit by digit
+// instead of as words: with a cutoff of 2025, 12345 is read "1 2 3 4 5" while
+// 1200 is read "twelve hundred"
+func (b *AssistantBuilder) WithNumberToDigitsCutoff(cutoff int) *AssistantBuilder {
+	b.formatPlan().NumberToDigitsCutoff = &cutoff
+	return b
+}
+
+// WithFormatters applies only the given formatters (see the Formatter
+// constants) to the text before the voice speaks it. Nil formatters keep
+// VAPI's defaults.
+func (b *AssistantBuilder) WithFormatters(formatters []string) *AssistantBuilder {
+	if formatters != nil {
+		b.formatPlan().FormattersEnabled = formatters
+	}
+	return b
+}
+
+// WithNumberFormatting configures how the voice reads numbers: numbers greater
+// than cutoff are read digit by digit (see WithNumberToDigitsCutoff), and only
+// the given formatters are applied (see WithFormatters). A cutoff of zero or
+// nil formatters keep VAPI's defaults.
+func (b *AssistantBuilder) WithNumberFormatting(cutoff int, formatters []string) *AssistantBuilder {
+	if cutoff > 0 {
+		b.WithNumberToDigitsCutoff(cutoff)
+	}
+	return b.WithFormatters(formatters)
+}
+
+// WithTextReplacements sets text replacements applied before the voice speaks
+func (b *AssistantBuilder) WithTextReplacements(replacements []TextReplacement) *AssistantBuilder {
+	b.formatPlan().Replacements = replacements
+	return b
+}
+
+// chunkPlan returns the voice chunk plan, initializing the voice and plan as needed
+func (b *AssistantBuilder) chunkPlan() *ChunkPlan {
+	if b.assistant.Voice == nil {
+		b.assistant.Voice = &Voice{}
+	}
+	if b.assistant.Voice.ChunkPlan == nil {
+		b.assistant.Voice.ChunkPlan = &ChunkPlan{}
+	}
+	return b.assistant.Voice.ChunkPlan
+}
+
+// formatPlan returns the voice format plan, enabling it and initializing the chunk plan as needed
+func (b *AssistantBuilder) formatPlan() *FormatPlan {
+	chunkPlan := b.chunkPlan()
+	if chunkPlan.FormatPlan == nil {
+		enabled := true
+		chunkPlan.FormatPlan = &FormatPlan{Enabled: &enabled}
+	}
+	return chunkPlan.FormatPlan
+}
+
 // WithFirstMessage sets the first message
 func (b *AssistantBuilder) WithFirstMessage(message string) *AssistantBuilder {
 	b.assistant.FirstMessage = &message
 	return b
 }
 
-// WithFirstMessageMode sets the first message mode
+// WithFirstMessageMode sets the first message mode, one of the FirstMessageMode
+// constants. Build doesn't reject unknown modes; use Validate to check them.
 func (b *AssistantBuilder) WithFirstMessageMode(mode string) *AssistantBuilder {
 	b.assistant.FirstMessageMode = &mode
 	return b
@@ -100,6 +173,71 @@ func (b *AssistantBuilder) WithTranscriber(provider, language string) *Assistant
 	return b
 }
 
+// WithTranscriberConfig sets a full transcriber configuration, such as one
+// created by NewDeepgramTranscriber or NewAssemblyAITranscriber
+func (b *AssistantBuilder) WithTranscriberConfig(transcriber *Transcriber) *AssistantBuilder {
+	b.assistant.Transcriber = transcriber
+	return b
+}
+
+// TranscriberTuning holds the transcriber's confidence and endpointing
+// settings. Nil fields leave the transcriber's setting unchanged; set them
+// with the pointer helpers, e.g. IntPtr(800).
+type TranscriberTuning struct {
+	// ConfidenceThreshold discards transcripts below this confidence (0-1)
+	ConfidenceThreshold *float64
+	// Endpointing is the silence in milliseconds that ends an utterance (Deepgram)
+	Endpointing *int
+	// EndOfTurnConfidenceThreshold is the confidence (0-1) needed to end a turn (AssemblyAI)
+	EndOfTurnConfidenceThreshold *float64
+	// MinEndOfTurnSilenceWhenConfident is the silence in milliseconds before
+	// a confident end of turn (AssemblyAI)
+	MinEndOfTurnSilenceWhenConfident *int
+	// MaxTurnSilence is the silence in milliseconds that ends a turn regardless of confidence (AssemblyAI)
+	MaxTurnSilence *int
+	// WordFinalizationMaxWaitTime is how long in milliseconds to wait for a word to be finalized (AssemblyAI)
+	WordFinalizationMaxWaitTime *int
+	// EndUtteranceSilenceThreshold is the silence in milliseconds that ends an utterance (AssemblyAI)
+	EndUtteranceSilenceThreshold *int
+}
+
+// WithTranscriberTuning applies confidence and endpointing settings to the
+// transcriber set by WithTranscriber or WithTranscriberConfig, e.g. to stop
+// end-of-turn detection from cutting callers off. Without a transcriber,
+// Validate reports an error.
+func (b *AssistantBuilder) WithTranscriberTuning(opts TranscriberTuning) *AssistantBuilder {
+	transcriber := b.assistant.Transcriber
+	if transcriber == nil {
+		if b.err == nil {
+			b.err = fmt.Errorf("transcriber tuning requires a transcriber; call WithTranscriber first")
+		}
+		return b
+	}
+
+	if opts.ConfidenceThreshold != nil {
+		transcriber.ConfidenceThreshold = opts.ConfidenceThreshold
+	}
+	if opts.Endpointing != nil {
+		transcriber.Endpointing = opts.Endpointing
+	}
+	if opts.EndOfTurnConfidenceThreshold != nil {
+		transcriber.EndOfTurnConfidenceThreshold = opts.EndOfTurnConfidenceThreshold
+	}
+	if opts.MinEndOfTurnSilenceWhenConfident != nil {
+		transcriber.MinEndOfTurnSilenceWhenConfident = opts.MinEndOfTurnSilenceWhenConfident
+	}
+	if opts.MaxTurnSilence != nil {
+		transcriber.MaxTurnSilence = opts.MaxTurnSilence
+	}
+	if opts.WordFinalizationMaxWaitTime != nil {
+		transcriber.WordFinalizationMaxWaitTime = opts.WordFinalizationMaxWaitTime
+	}
+	if opts.EndUtteranceSilenceThreshold != nil {
+		transcriber.EndUtteranceSilenceThreshold = opts.EndUtteranceSilenceThreshold
+	}
+	return b
+}
+
 // WithTemperature sets the model temperature
 func (b *AssistantBuilder) WithTemperature(temp float64) *AssistantBuilder {
 	if b.assistant.Model == nil {
@@ -118,6 +256,211 @@ func (b *AssistantBuilder) WithMaxTokens(tokens int) *AssistantBuilder {
 	return b
 }
 
+// WithNumFastTurns sets how many initial turns use the faster model path
+func (b *AssistantBuilder) WithNumFastTurns(turns int) *AssistantBuilder {
+	if b.assistant.Model == nil {
+		b.assistant.Model = &Model{}
+	}
+	b.assistant.Model.NumFastTurns = &turns
+	return b
+}
+
+// WithEmotionRecognition enables or disables emotion recognition for the model
+func (b *AssistantBuilder) WithEmotionRecognition(enabled bool) *AssistantBuilder {
+	if b.assistant.Model == nil {
+		b.assistant.Model = &Model{}
+	}
+	b.assistant.Model.EmotionRecognitionEnabled = &enabled
+	return b
+}
+
+// WithThinking sets the model extended reasoning configuration
+func (b *AssistantBuilder) WithThinking(thinkingType string, budgetTokens int) *AssistantBuilder {
+	if b.assistant.Model == nil {
+		b.assistant.Model = &Model{}
+	}
+	b.assistant.Model.Thinking = &ThinkingConfig{
+		Type:         thinkingType,
+		BudgetTokens: &budgetTokens,
+	}
+	return b
+}
+
+// WithHIPAA enables or disables HIPAA compliance mode
+func (b *AssistantBuilder) WithHIPAA(enabled bool) *AssistantBuilder {
+	if b.assistant.CompliancePlan == nil {
+		b.assistant.CompliancePlan = &CompliancePlan{}
+	}
+	b.assistant.CompliancePlan.HIPAAEnabled = &HIPAAConfig{HIPAAEnabled: enabled}
+	return b
+}
+
+// WithPCI enables or disables PCI compliance mode
+func (b *AssistantBuilder) WithPCI(enabled bool) *AssistantBuilder {
+	if b.assistant.CompliancePlan == nil {
+		b.assistant.CompliancePlan = &CompliancePlan{}
+	}
+	b.assistant.CompliancePlan.PCIEnabled = &PCIConfig{PCIEnabled: enabled}
+	return b
+}
+
+// WithCredential adds a provider API key credential
+func (b *AssistantBuilder) WithCredential(provider, apiKey string) *AssistantBuilder {
+	b.assistant.Credentials = append(b.assistant.Credentials, Credential{
+		Provider: provider,
+		APIKey:   apiKey,
+	})
+	return b
+}
+
+// WithCredentialIDs sets the IDs of stored provider credentials
+func (b *AssistantBuilder) WithCredentialIDs(credentialIDs []string) *AssistantBuilder {
+	b.assistant.CredentialIDs = credentialIDs
+	return b
+}
+
+// WithObservability sets the observability provider used to export call traces.
+// Tags and metadata are merged into any already set rather than replacing them;
+// metadata keys set here win over existing ones.
+func (b *AssistantBuilder) WithObservability(provider string, tags []string, metadata map[string]interface{}) *AssistantBuilder {
+	plan := b.observabilityPlan()
+	if provider != "" {
+		plan.Provider = provider
+	}
+	plan.Tags = mergeTags(plan.Tags, tags)
+	if len(metadata) > 0 {
+		merged := make(map[string]interface{}, len(plan.Metadata)+len(metadata))
+		for key, value := range plan.Metadata {
+			merged[key] = value
+		}
+		for key, value := range metadata {
+			merged[key] = value
+		}
+		plan.Metadata = merged
+	}
+	return b
+}
+
+// AddObservabilityTags appends tags to the observability plan, skipping duplicates
+func (b *AssistantBuilder) AddObservabilityTags(tags ...string) *AssistantBuilder {
+	plan := b.observabilityPlan()
+	plan.Tags = mergeTags(plan.Tags, tags)
+	return b
+}
+
+// observabilityPlan returns the assistant's observability plan, creating it if needed
+func (b *AssistantBuilder) observabilityPlan() *ObservabilityPlan {
+	if b.assistant.ObservabilityPlan == nil {
+		b.assistant.ObservabilityPlan = &ObservabilityPlan{}
+	}
+	return b.assistant.ObservabilityPlan
+}
+
+// mergeTags returns a new slice with the tags of both lists in order, without duplicates
+func mergeTags(existing, tags []string) []string {
+	if len(tags) == 0 {
+		return existing
+	}
+	merged := make([]string, 0, len(existing)+len(tags))
+	seen := make(map[string]bool, len(existing)+len(tags))
+	for _, tag := range append(append([]string{}, existing...), tags...) {
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		merged = append(merged, tag)
+	}
+	return merged
+}
+
+// WithClientMessages sets the message types VAPI sends to the client SDK
+func (b *AssistantBuilder) WithClientMessages(messageTypes []string) *AssistantBuilder {
+	b.assistant.ClientMessages = messageTypes
+	return b
+}
+
+// WithServerMessages sets the message types VAPI sends to the server URL
+func (b *AssistantBuilder) WithServerMessages(messageTypes []string) *AssistantBuilder {
+	b.assistant.ServerMessages = messageTypes
+	return b
+}
+
+// WithStructuredDataMulti sets the named structured data objects extracted from each call
+func (b *AssistantBuilder) WithStructuredDataMulti(items []StructuredDataMultiItem) *AssistantBuilder {
+	b.analysisPlan().StructuredDataMultiPlan = items
+	return b
+}
+
+// AddStructuredDataPlan adds an enabled structured data plan extracting an
+// object matching schema under key, replacing any existing plan for that key
+func (b *AssistantBuilder) AddStructuredDataPlan(key string, schema *Schema) *AssistantBuilder {
+	enabled := true
+	item := StructuredDataMultiItem{
+		Key: key,
+		Plan: &StructuredDataPlan{
+			Enabled: &enabled,
+			Schema:  schema,
+		},
+	}
+
+	plan := b.analysisPlan()
+	for i := range plan.StructuredDataMultiPlan {
+		if plan.StructuredDataMultiPlan[i].Key == key {
+			plan.StructuredDataMultiPlan[i] = item
+			return b
+		}
+	}
+	plan.StructuredDataMultiPlan = append(plan.StructuredDataMultiPlan, item)
+	return b
+}
+
+// AddHook adds a hook run on an assistant event, e.g. one created with OnCallEnding
+func (b *AssistantBuilder) AddHook(hook Hook) *AssistantBuilder {
+	b.assistant.Hooks = append(b.assistant.Hooks, hook)
+	return b
+}
+
+// analysisPlan returns the assistant's analysis plan, creating it if needed
+func (b *AssistantBuilder) analysisPlan() *AnalysisPlan {
+	if b.assistant.AnalysisPlan == nil {
+		b.assistant.AnalysisPlan = &AnalysisPlan{}
+	}
+	return b.assistant.AnalysisPlan
+}
+
+// WithRecording configures call recording. A format (e.g. "wav;l16" or
+// "mp3") left empty keeps VAPI's default.
+func (b *AssistantBuilder) WithRecording(enabled bool, format string) *AssistantBuilder {
+	if b.assistant.ArtifactPlan == nil {
+		b.assistant.ArtifactPlan = &ArtifactPlan{}
+	}
+	b.assistant.ArtifactPlan.RecordingEnabled = &enabled
+	if format != "" {
+		b.assistant.ArtifactPlan.RecordingFormat = &format
+	}
+	return b
+}
+
+// WithTwilioRecordingChannels sets the recording channels ("mono" or "dual",
+// which records the assistant and customer on separate channels) on the
+// Twilio transport configuration, adding one if the assistant has none.
+// Twilio is the only transport that supports recording channels.
+func (b *AssistantBuilder) WithTwilioRecordingChannels(channels string) *AssistantBuilder {
+	var transport *TransportConfiguration
+	for i := range b.assistant.TransportConfigurations {
+		if b.assistant.TransportConfigurations[i].Provider == "twilio" {
+			transport = &b.assistant.TransportConfigurations[i]
+			break
+		}
+	}
+	if transport == nil {
+		b.assistant.TransportConfigurations = append(b.assistant.TransportConfigurations, TransportConfiguration{Provider: "twilio"})
+		transport = &b.assistant.TransportConfigurations[len(b.assistant.TransportConfigurations)-1]
+	}
+	transport.RecordingChannels = &channels
+	return b
+}
+
 // WithName sets the assistant name
 func (b *AssistantBuilder) WithName(name string) *AssistantBuilder {
 	b.assistant.Name = &name
@@ -135,6 +478,20 @@ func (b *AssistantBuilder) Build() *Assistant {
 	return b.assistant
 }
 
+// Validate validates the built assistant, also reporting the first error
+// from a builder method such as WithTranscriberTuning
+func (b *AssistantBuilder) Validate() error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.assistant.FirstMessageMode != nil {
+		if err := ValidateFirstMessageMode(*b.assistant.FirstMessageMode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // RequestBuilder helps build CreateChatRequest configurations
 type RequestBuilder struct {
 	request *CreateChatRequest
@@ -201,6 +558,28 @@ func (b *RequestBuilder) WithAssistantOverrides(overrides *AssistantOverrides) *
 	return b
 }
 
+// WithMaxDurationOverride caps the assistant's maximum duration for this chat,
+// keeping any other overrides already set
+func (b *RequestBuilder) WithMaxDurationOverride(seconds int) *RequestBuilder {
+	b.assistantOverrides().MaxDurationSeconds = &seconds
+	return b
+}
+
+// WithVariableValues sets template variables such as {{name}} for this chat,
+// keeping any other overrides and variables already set
+func (b *RequestBuilder) WithVariableValues(values map[string]interface{}) *RequestBuilder {
+	b.assistantOverrides().VariableValues = mergeVariableValues(b.assistantOverrides().VariableValues, values)
+	return b
+}
+
+// assistantOverrides returns the request's assistant overrides, creating them if needed
+func (b *RequestBuilder) assistantOverrides() *AssistantOverrides {
+	if b.request.AssistantOverrides == nil {
+		b.request.AssistantOverrides = &AssistantOverrides{}
+	}
+	return b.request.AssistantOverrides
+}
+
 // Build returns the built CreateChatRequest
 func (b *RequestBuilder) Build() *CreateChatRequest {
 	return b.request
@@ -212,6 +591,10 @@ func (b *RequestBuilder) Validate() error {
 		return fmt.Errorf("input is required")
 	}
 
+	if err := validateInput(b.request.Input); err != nil {
+		return err
+	}
+
 	// Validate that at least one of assistantId, assistant, sessionId, or previousChatId is provided
 	if b.request.AssistantID == nil && b.request.Assistant == nil && b.request.SessionID == nil && b.request.PreviousChatID == nil {
 		return fmt.Errorf("at least one of assistantId, assistant, sessionId, or previousChatId is required")
@@ -230,6 +613,158 @@ func (b *RequestBuilder) Validate() error {
 	return nil
 }
 
+// AssistantOverridesBuilder helps build AssistantOverrides for chats and calls
+type AssistantOverridesBuilder struct {
+	overrides *AssistantOverrides
+}
+
+// NewAssistantOverridesBuilder creates a new AssistantOverridesBuilder
+func NewAssistantOverridesBuilder() *AssistantOverridesBuilder {
+	return &AssistantOverridesBuilder{
+		overrides: &AssistantOverrides{},
+	}
+}
+
+// WithVariableValue sets a template variable, e.g. "name" for {{name}}
+func (b *AssistantOverridesBuilder) WithVariableValue(key string, value interface{}) *AssistantOverridesBuilder {
+	b.overrides.VariableValues = mergeVariableValues(b.overrides.VariableValues, map[string]interface{}{key: value})
+	return b
+}
+
+// WithVariableValues sets several template variables, keeping those already set
+func (b *AssistantOverridesBuilder) WithVariableValues(values map[string]interface{}) *AssistantOverridesBuilder {
+	b.overrides.VariableValues = mergeVariableValues(b.overrides.VariableValues, values)
+	return b
+}
+
+// WithFirstMessage overrides the first message
+func (b *AssistantOverridesBuilder) WithFirstMessage(message string) *AssistantOverridesBuilder {
+	b.overrides.FirstMessage = &message
+	return b
+}
+
+// WithFirstMessageMode overrides the first message mode, see the FirstMessageMode constants
+func (b *AssistantOverridesBuilder) WithFirstMessageMode(mode string) *AssistantOverridesBuilder {
+	b.overrides.FirstMessageMode = &mode
+	return b
+}
+
+// WithMaxDurationSeconds overrides the maximum duration
+func (b *AssistantOverridesBuilder) WithMaxDurationSeconds(seconds int) *AssistantOverridesBuilder {
+	b.overrides.MaxDurationSeconds = &seconds
+	return b
+}
+
+// Build returns the built AssistantOverrides
+func (b *AssistantOverridesBuilder) Build() *AssistantOverrides {
+	return b.overrides
+}
+
+// mergeVariableValues adds values to existing, creating the map if needed
+func mergeVariableValues(existing, values map[string]interface{}) map[string]interface{} {
+	if existing == nil && len(values) > 0 {
+		existing = make(map[string]interface{}, len(values))
+	}
+	for key, value := range values {
+		existing[key] = value
+	}
+	return existing
+}
+
+// SchemaBuilder helps build object Schema configurations for tool parameters
+type SchemaBuilder struct {
+	schema   *Schema
+	required []string
+}
+
+// NewSchemaBuilder creates a new SchemaBuilder for an object schema
+func NewSchemaBuilder() *SchemaBuilder {
+	objectType := "object"
+	return &SchemaBuilder{
+		schema: &Schema{
+			Type:       &objectType,
+			Properties: make(map[string]interface{}),
+		},
+	}
+}
+
+// WithDescription sets the schema description
+func (b *SchemaBuilder) WithDescription(description string) *SchemaBuilder {
+	b.schema.Description = &description
+	return b
+}
+
+// AddProperty adds a property with a custom schema
+func (b *SchemaBuilder) AddProperty(name string, property *Schema, required bool) *SchemaBuilder {
+	b.schema.Properties[name] = property
+	if required {
+		b.required = append(b.required, name)
+	}
+	return b
+}
+
+// AddStringProperty adds a string property
+func (b *SchemaBuilder) AddStringProperty(name, description string, required bool) *SchemaBuilder {
+	return b.addTypedProperty(name, "string", description, required)
+}
+
+// AddNumberProperty adds a number property
+func (b *SchemaBuilder) AddNumberProperty(name, description string, required bool) *SchemaBuilder {
+	return b.addTypedProperty(name, "number", description, required)
+}
+
+// AddIntegerProperty adds an integer property
+func (b *SchemaBuilder) AddIntegerProperty(name, description string, required bool) *SchemaBuilder {
+	return b.addTypedProperty(name, "integer", description, required)
+}
+
+// AddBooleanProperty adds a boolean property
+func (b *SchemaBuilder) AddBooleanProperty(name, description string, required bool) *SchemaBuilder {
+	return b.addTypedProperty(name, "boolean", description, required)
+}
+
+// AddEnumProperty adds a string property restricted to the given values
+func (b *SchemaBuilder) AddEnumProperty(name string, values []string) *SchemaBuilder {
+	stringType := "string"
+	return b.AddProperty(name, &Schema{
+		Type: &stringType,
+		Enum: values,
+	}, false)
+}
+
+// Required marks existing or later-added properties as required
+func (b *SchemaBuilder) Required(names ...string) *SchemaBuilder {
+	b.required = append(b.required, names...)
+	return b
+}
+
+// addTypedProperty adds a property of a primitive type
+func (b *SchemaBuilder) addTypedProperty(name, propertyType, description string, required bool) *SchemaBuilder {
+	property := &Schema{Type: &propertyType}
+	if description != "" {
+		property.Description = &description
+	}
+	return b.AddProperty(name, property, required)
+}
+
+// Build returns the built Schema, checking that every required name is a property
+func (b *SchemaBuilder) Build() (*Schema, error) {
+	seen := make(map[string]bool)
+	var required []string
+	for _, name := range b.required {
+		if _, ok := b.schema.Properties[name]; !ok {
+			return nil, fmt.Errorf("required property %q is not defined", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			required = append(required, name)
+		}
+	}
+	b.schema.Required = required
+
+	return b.schema, nil
+}
+
 // Helper functions for common assistant configurations
 
 // CreateAnthropicAssistant creates a basic Anthropic Claude assistant
@@ -240,7 +775,7 @@ func CreateAnthropicAssistant(systemPrompt string) *Assistant {
 		WithTemperature(0.7).
 		WithMaxTokens(1000).
 		WithFirstMessage("Hello! How can I help you today?").
-		WithFirstMessageMode("assistant-speaks-first").
+		WithFirstMessageMode(FirstMessageModeAssistantSpeaksFirst).
 		Build()
 }
 
@@ -252,7 +787,7 @@ func CreateOpenAIAssistant(systemPrompt string) *Assistant {
 		WithTemperature(0.7).
 		WithMaxTokens(1000).
 		WithFirstMessage("Hello! How can I help you today?").
-		WithFirstMessageMode("assistant-speaks-first").
+		WithFirstMessageMode(FirstMessageModeAssistantSpeaksFirst).
 		Build()
 }
 
@@ -274,7 +809,7 @@ Always be helpful, informative, and focused on providing value to potential cust
 		WithTemperature(0.7).
 		WithMaxTokens(1500).
 		WithFirstMessage(fmt.Sprintf("Hello! I'm here to help you learn more about %s's %s services. How can I assist you today?", companyName, industry)).
-		WithFirstMessageMode("assistant-speaks-first").
+		WithFirstMessageMode(FirstMessageModeAssistantSpeaksFirst).
 		WithName(fmt.Sprintf("%s Sales Assistant", companyName)).
 		Build()
 }
@@ -300,13 +835,71 @@ Conoces bien el mercado colombiano y las necesidades específicas de conectivida
 		WithTemperature(0.7).
 		WithMaxTokens(1500).
 		WithFirstMessage("¡Hola! Soy tu asistente especializado en servicios de fibra óptica. ¿Te interesa conocer nuestros planes de internet de alta velocidad?").
-		WithFirstMessageMode("assistant-speaks-first").
+		WithFirstMessageMode(FirstMessageModeAssistantSpeaksFirst).
 		WithName("Asistente de Fibra Óptica").
 		WithTranscriber("assembly-ai", "es").
 		WithVoice("azure", "es-CO-SalomeNeural").
 		Build()
 }
 
+// First message modes
+const (
+	FirstMessageModeAssistantSpeaksFirst               = "assistant-speaks-first"
+	FirstMessageModeAssistantSpeaksFirstModelGenerated = "assistant-speaks-first-with-model-generated-message"
+	FirstMessageModeAssistantWaitsForUser              = "assistant-waits-for-user"
+)
+
+// FirstMessageModes lists the valid first message modes
+var FirstMessageModes = []string{
+	FirstMessageModeAssistantSpeaksFirst,
+	FirstMessageModeAssistantSpeaksFirstModelGenerated,
+	FirstMessageModeAssistantWaitsForUser,
+}
+
+// ValidateFirstMessageMode returns an error if mode isn't a known first message mode
+func ValidateFirstMessageMode(mode string) error {
+	for _, valid := range FirstMessageModes {
+		if mode == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid first message mode %q, must be one of: %s", mode, strings.Join(FirstMessageModes, ", "))
+}
+
+// Helper functions for creating transcribers
+
+// Transcriber providers
+const (
+	TranscriberProviderDeepgram   = "deepgram"
+	TranscriberProviderAssemblyAI = "assembly-ai"
+)
+
+// NewDeepgramTranscriber creates a Deepgram transcriber using the nova-2
+// model, which supports the widest range of languages, with smart formatting
+// enabled. Boost specific terms by setting Keywords to entries like "VAPI:2".
+func NewDeepgramTranscriber(language string) *Transcriber {
+	model := "nova-2"
+	smartFormat := true
+	return &Transcriber{
+		Provider:    TranscriberProviderDeepgram,
+		Model:       &model,
+		Language:    &language,
+		SmartFormat: &smartFormat,
+	}
+}
+
+// NewAssemblyAITranscriber creates an AssemblyAI transcriber with turn
+// formatting enabled and wordBoost biasing recognition towards the given terms
+func NewAssemblyAITranscriber(language string, wordBoost []string) *Transcriber {
+	formatTurns := true
+	return &Transcriber{
+		Provider:    TranscriberProviderAssemblyAI,
+		Language:    &language,
+		FormatTurns: &formatTurns,
+		WordBoost:   wordBoost,
+	}
+}
+
 // Helper functions for creating chat messages
 
 // CreateChatMessage creates a new chat message
@@ -343,6 +936,233 @@ func CreateSystemMessage(message string) ChatMessage {
 	}
 }
 
+// Helper functions for creating tool messages
+
+// Tool message types
+const (
+	ToolMessageRequestStart           = "request-start"
+	ToolMessageRequestComplete        = "request-complete"
+	ToolMessageRequestFailed          = "request-failed"
+	ToolMessageRequestResponseDelayed = "request-response-delayed"
+)
+
+// CreateToolMessage creates a tool message of the given type
+func CreateToolMessage(messageType, content string) ToolMessage {
+	return ToolMessage{
+		Type:    messageType,
+		Content: &content,
+	}
+}
+
+// RequestStartMessage creates a message spoken when a tool call starts
+func RequestStartMessage(content string) ToolMessage {
+	return CreateToolMessage(ToolMessageRequestStart, content)
+}
+
+// RequestCompleteMessage creates a message spoken when a tool call completes
+func RequestCompleteMessage(content string) ToolMessage {
+	return CreateToolMessage(ToolMessageRequestComplete, content)
+}
+
+// RequestFailedMessage creates a message spoken when a tool call fails
+func RequestFailedMessage(content string) ToolMessage {
+	return CreateToolMessage(ToolMessageRequestFailed, content)
+}
+
+// RequestResponseDelayedMessage creates a message spoken when a tool call is slow to respond
+func RequestResponseDelayedMessage(content string) ToolMessage {
+	return CreateToolMessage(ToolMessageRequestResponseDelayed, content)
+}
+
+// CreateMultilingualToolMessage creates a tool message with per-language contents
+func CreateMultilingualToolMessage(messageType string, contents ...MessageContent) ToolMessage {
+	return ToolMessage{
+		Type:     messageType,
+		Contents: contents,
+	}
+}
+
+// NewMultilingualToolMessage creates a tool message with one text content per
+// language, e.g. {"en": "One moment please", "es": "Un momento por favor"}.
+// Contents are ordered by language code so the result is deterministic.
+func NewMultilingualToolMessage(msgType string, byLanguage map[string]string) ToolMessage {
+	languages := make([]string, 0, len(byLanguage))
+	for language := range byLanguage {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	contents := make([]MessageContent, 0, len(languages))
+	for _, language := range languages {
+		contents = append(contents, CreateTextContent(byLanguage[language], language))
+	}
+	return CreateMultilingualToolMessage(msgType, contents...)
+}
+
+// CreateTextContent creates text message content in the given language
+func CreateTextContent(text, language string) MessageContent {
+	content := MessageContent{
+		Type: "text",
+		Text: &text,
+	}
+	if language != "" {
+		content.Language = &language
+	}
+	return content
+}
+
+// Helper functions for creating hooks
+
+// Hook triggers
+const (
+	HookOnCallEnding                 = "call.ending"
+	HookOnAssistantSpeechInterrupted = "assistant.speech.interrupted"
+	HookOnCustomerSpeechInterrupted  = "customer.speech.interrupted"
+	HookOnCustomerSpeechTimeout      = "customer.speech.timeout"
+)
+
+// HookActionTool is the type of hook actions that run a tool
+const HookActionTool = "tool"
+
+// HookFilterOneOf is the type of hook filters matching a key against a list of values
+const HookFilterOneOf = "oneOf"
+
+// HookFilterKeyEndedReason filters call.ending hooks on why the call ended
+const HookFilterKeyEndedReason = "call.endedReason"
+
+// NewHook creates a hook that runs actions when the on trigger fires
+func NewHook(on string, actions ...HookAction) Hook {
+	return Hook{
+		On: on,
+		Do: actions,
+	}
+}
+
+// OnCallEnding creates a hook that runs actions when the call is ending
+func OnCallEnding(actions ...HookAction) Hook {
+	return NewHook(HookOnCallEnding, actions...)
+}
+
+// OnAssistantSpeechInterrupted creates a hook that runs actions when the customer interrupts the assistant
+func OnAssistantSpeechInterrupted(actions ...HookAction) Hook {
+	return NewHook(HookOnAssistantSpeechInterrupted, actions...)
+}
+
+// OnCustomerSpeechInterrupted creates a hook that runs actions when the assistant interrupts the customer
+func OnCustomerSpeechInterrupted(actions ...HookAction) Hook {
+	return NewHook(HookOnCustomerSpeechInterrupted, actions...)
+}
+
+// OnCustomerSpeechTimeout creates a hook that runs actions when the customer stays silent
+func OnCustomerSpeechTimeout(actions ...HookAction) Hook {
+	return NewHook(HookOnCustomerSpeechTimeout, actions...)
+}
+
+// ToolAction creates a hook action that runs the saved tool with toolID
+func ToolAction(toolID string) HookAction {
+	return HookAction{
+		Type:   HookActionTool,
+		ToolID: &toolID,
+	}
+}
+
+// InlineToolAction creates a hook action that runs an inline tool definition
+func InlineToolAction(tool Tool) HookAction {
+	return HookAction{
+		Type: HookActionTool,
+		Tool: &tool,
+	}
+}
+
+// EndedReasonFilter creates a filter matching calls that ended for one of reasons
+func EndedReasonFilter(reasons ...string) HookFilter {
+	return HookFilter{
+		Type:  HookFilterOneOf,
+		Key:   HookFilterKeyEndedReason,
+		OneOf: reasons,
+	}
+}
+
+// Voice formatters, applied to the text before the voice speaks it
+const (
+	FormatterMarkdown      = "markdown"
+	FormatterAsterisk      = "asterisk"
+	FormatterQuote         = "quote"
+	FormatterDash          = "dash"
+	FormatterNewline       = "newline"
+	FormatterColon         = "colon"
+	FormatterAcronym       = "acronym"
+	FormatterDollarAmount  = "dollarAmount"
+	FormatterEmail         = "email"
+	FormatterDate          = "date"
+	FormatterTime          = "time"
+	FormatterDistance      = "distance"
+	FormatterUnit          = "unit"
+	FormatterPercentage    = "percentage"
+	FormatterPhoneNumber   = "phoneNumber"
+	FormatterNumber        = "number"
+	FormatterStripAsterisk = "stripAsterisk"
+)
+
+// Helper functions for creating transfer tools
+
+// ToolTypeTransferCall is the type of tools that transfer the call
+const ToolTypeTransferCall = "transferCall"
+
+// Transfer destination types
+const (
+	TransferDestinationNumber    = "number"
+	TransferDestinationSIP       = "sip"
+	TransferDestinationAssistant = "assistant"
+)
+
+// NewTransferTool creates a transferCall tool that can transfer to any of destinations
+func NewTransferTool(destinations []TransferDestination) Tool {
+	return Tool{
+		Type:         ToolTypeTransferCall,
+		Name:         ToolTypeTransferCall,
+		Destinations: destinations,
+	}
+}
+
+// NumberDestination creates a destination that transfers to a phone number.
+// message, if not empty, is spoken to the customer before transferring.
+func NumberDestination(number, message string) TransferDestination {
+	destination := TransferDestination{
+		Type:   TransferDestinationNumber,
+		Number: &number,
+	}
+	if message != "" {
+		destination.Message = &message
+	}
+	return destination
+}
+
+// SIPDestination creates a destination that transfers to a SIP URI
+func SIPDestination(sipURI, message string) TransferDestination {
+	destination := TransferDestination{
+		Type:   TransferDestinationSIP,
+		SipURI: &sipURI,
+	}
+	if message != "" {
+		destination.Message = &message
+	}
+	return destination
+}
+
+// AssistantDestination creates a destination that hands the call to another
+// assistant. VAPI identifies the assistant by its name within the squad.
+func AssistantDestination(assistantName, message string) TransferDestination {
+	destination := TransferDestination{
+		Type:          TransferDestinationAssistant,
+		AssistantName: &assistantName,
+	}
+	if message != "" {
+		destination.Message = &message
+	}
+	return destination
+}
+
 // Helper functions for common request patterns
 
 // CreateSimpleTextRequest creates a simple text-based chat request
@@ -378,6 +1198,14 @@ func CreateContinuationRequest(text, previousChatID string) *CreateChatRequest {
 		Build()
 }
 
+// CreateMessageContinuationRequest creates a request to continue a previous chat with message history
+func CreateMessageContinuationRequest(messages []ChatMessage, previousChatID string) *CreateChatRequest {
+	return NewRequestBuilder().
+		WithMessageInput(messages).
+		WithPreviousChatID(previousChatID).
+		Build()
+}
+
 // CreateSessionRequest creates a request within a session
 func CreateSessionRequest(text, sessionID string) *CreateChatRequest {
 	return NewRequestBuilder().
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,204 +13,402 @@ import (
 	"time"
 
 	"github.com/heirloomz/vapi-go-library/pkg/config"
+	"github.com/heirloomz/vapi-go-library/pkg/httputil"
 )
 
 // Client represents a VAPI chat client
 type Client struct {
 	config     *config.Config
 	httpClient *http.Client
+
+	// Reconnect attempts after a dropped stream, see SetStreamReconnect
+	streamReconnects     int
+	streamReconnectDelay time.Duration
+
+	// Stream parsing, see SetStreamDoneSentinel and HandleStreamEvent
+	streamDoneSentinel  string
+	streamEventHandlers map[string]StreamEventHandler
+
+	// Check assistants exist before chatting, see SetAssistantPreflight
+	assistantPreflight bool
+
+	// Records created chats and sessions, see SetResourceTracker
+	resources ResourceRecorder
 }
 
 // NewClient creates a new VAPI chat client
 func NewClient(cfg *config.Config) *Client {
+	var transport http.RoundTripper = cfg.VAPI.Transport.NewTransport()
+	if cfg.VAPI.HeaderProvider != nil {
+		transport = &httputil.HeaderTransport{Base: transport, Headers: cfg.VAPI.HeaderProvider}
+	}
+	httpClient := &http.Client{
+		Timeout:   cfg.VAPI.Timeout,
+		Transport: &httputil.GzipTransport{Base: transport},
+	}
+
 	return &Client{
-		config: cfg,
-		httpClient: &http.Client{
-			Timeout: cfg.VAPI.Timeout,
-		},
+		config:     cfg,
+		httpClient: httpClient,
 	}
 }
 
 // CreateChat creates a new chat with the VAPI API
 func (c *Client) CreateChat(ctx context.Context, req *CreateChatRequest) (*ChatResponse, error) {
+	httpReq, _, err := c.newChatRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkAssistant(ctx, req.AssistantID); err != nil {
+		return nil, err
+	}
+
+	// Send request
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// Reject non-JSON bodies such as HTML error pages from a proxy
+	if err := httputil.CheckJSONResponse(resp, body); err != nil {
+		return nil, err
+	}
+
+	// Check for HTTP errors
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	// Parse response
+	var chatResponse ChatResponse
+	if err := json.Unmarshal(body, &chatResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	c.recordChat(chatResponse.ID)
+	return &chatResponse, nil
+}
+
+// newChatRequest validates a CreateChatRequest and builds the HTTP request
+// for it, returning the request along with its JSON body
+func (c *Client) newChatRequest(ctx context.Context, req *CreateChatRequest) (*http.Request, []byte, error) {
 	if req == nil {
-		return nil, fmt.Errorf("request cannot be nil")
+		return nil, nil, fmt.Errorf("request cannot be nil")
 	}
 
 	if req.Input == nil {
-		return nil, fmt.Errorf("input is required")
+		return nil, nil, fmt.Errorf("input is required")
+	}
+
+	if err := validateInput(req.Input); err != nil {
+		return nil, nil, err
 	}
 
 	// Validate that at least one of assistantId, assistant, sessionId, or previousChatId is provided
 	if req.AssistantID == nil && req.Assistant == nil && req.SessionID == nil && req.PreviousChatID == nil {
-		return nil, fmt.Errorf("at least one of assistantId, assistant, sessionId, or previousChatId is required")
+		return nil, nil, fmt.Errorf("at least one of assistantId, assistant, sessionId, or previousChatId is required")
 	}
 
 	// Validate that sessionId and previousChatId are mutually exclusive
 	if req.SessionID != nil && req.PreviousChatID != nil {
-		return nil, fmt.Errorf("sessionId and previousChatId are mutually exclusive")
+		return nil, nil, fmt.Errorf("sessionId and previousChatId are mutually exclusive")
 	}
 
 	// Marshal request to JSON
 	jsonData, err := json.Marshal(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Create HTTP request
 	url := fmt.Sprintf("%s/chat", c.config.VAPI.BaseURL)
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	// Set headers
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.config.VAPI.APIToken)
-
-	// Send request
-	resp, err := c.httpClient.Do(httpReq)
+	httpReq.Header.Set("User-Agent", c.userAgent())
+	token, err := c.config.VAPI.Token(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return nil, nil, err
 	}
-	defer resp.Body.Close()
+	httpReq.Header.Set("Authorization", "Bearer "+token)
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
+	return httpReq, jsonData, nil
+}
 
-	// Check for HTTP errors
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
+// CreateStreamingChat creates a new streaming chat with the VAPI API
+func (c *Client) CreateStreamingChat(ctx context.Context, req *CreateChatRequest) (<-chan *StreamingChatResponse, <-chan error) {
+	responseChan := make(chan *StreamingChatResponse, 100)
+	errorChan := make(chan error, 1)
 
-	// Parse response
-	var chatResponse ChatResponse
-	if err := json.Unmarshal(body, &chatResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
+	go func() {
+		defer close(responseChan)
+		defer close(errorChan)
 
-	return &chatResponse, nil
+		err := c.StreamChat(ctx, req, func(streamResponse *StreamingChatResponse) error {
+			// Send response to channel
+			select {
+			case responseChan <- streamResponse:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			errorChan <- err
+		}
+	}()
+
+	return responseChan, errorChan
 }
 
-// CreateStreamingChat creates a new streaming chat with the VAPI API
-func (c *Client) CreateStreamingChat(ctx context.Context, req *CreateChatRequest) (<-chan *StreamingChatResponse, <-chan error) {
+// CreateStreamingChatWithResult is like CreateStreamingChat but also returns a
+// channel that receives the final chat metadata once the stream completes
+// successfully. All three channels are closed when the stream ends.
+func (c *Client) CreateStreamingChatWithResult(ctx context.Context, req *CreateChatRequest) (<-chan *StreamingChatResponse, <-chan *StreamResult, <-chan error) {
 	responseChan := make(chan *StreamingChatResponse, 100)
+	resultChan := make(chan *StreamResult, 1)
 	errorChan := make(chan error, 1)
 
 	go func() {
 		defer close(responseChan)
+		defer close(resultChan)
 		defer close(errorChan)
 
-		if req == nil {
-			errorChan <- fmt.Errorf("request cannot be nil")
+		result, err := c.StreamChatWithResult(ctx, req, func(streamResponse *StreamingChatResponse) error {
+			select {
+			case responseChan <- streamResponse:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				errorChan <- err
+			}
 			return
 		}
+		resultChan <- result
+	}()
 
-		if req.Input == nil {
-			errorChan <- fmt.Errorf("input is required")
-			return
+	return responseChan, resultChan, errorChan
+}
+
+// StreamChat creates a new streaming chat and calls onDelta for each streamed
+// frame. It returns once the stream is done, the context is cancelled, or
+// onDelta returns an error, which aborts the stream and is returned as-is.
+func (c *Client) StreamChat(ctx context.Context, req *CreateChatRequest, onDelta func(*StreamingChatResponse) error) error {
+	_, err := c.StreamChatWithResult(ctx, req, onDelta)
+	return err
+}
+
+// StreamChatWithResult is like StreamChat but also returns the final chat
+// metadata (chat ID, previous chat ID, cost) collected from the streamed
+// frames once the stream completes.
+func (c *Client) StreamChatWithResult(ctx context.Context, req *CreateChatRequest, onDelta func(*StreamingChatResponse) error) (*StreamResult, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	if onDelta == nil {
+		return nil, fmt.Errorf("onDelta callback is required")
+	}
+
+	if req.Input == nil {
+		return nil, fmt.Errorf("input is required")
+	}
+
+	if err := validateInput(req.Input); err != nil {
+		return nil, err
+	}
+
+	// Validate that at least one of assistantId, assistant, sessionId, or previousChatId is provided
+	if req.AssistantID == nil && req.Assistant == nil && req.SessionID == nil && req.PreviousChatID == nil {
+		return nil, fmt.Errorf("at least one of assistantId, assistant, sessionId, or previousChatId is required")
+	}
+
+	// Validate that sessionId and previousChatId are mutually exclusive
+	if req.SessionID != nil && req.PreviousChatID != nil {
+		return nil, fmt.Errorf("sessionId and previousChatId are mutually exclusive")
+	}
+
+	if err := c.checkAssistant(ctx, req.AssistantID); err != nil {
+		return nil, err
+	}
+
+	// Enable streaming
+	streamReq := *req
+	streamReq.Stream = &[]bool{true}[0]
+
+	// Marshal request to JSON
+	jsonData, err := json.Marshal(&streamReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	result := &StreamResult{PreviousChatID: req.PreviousChatID, SessionID: req.SessionID}
+	// The chat exists once its first frame arrived, even if the stream fails
+	defer func() { c.recordChat(result.ChatID) }()
+	reader := &sseReader{}
+	for attempt := 0; ; attempt++ {
+		err := c.streamOnce(ctx, jsonData, reader, result, onDelta)
+		if err == nil {
+			return result, nil
 		}
 
-		// Validate that at least one of assistantId, assistant, sessionId, or previousChatId is provided
-		if req.AssistantID == nil && req.Assistant == nil && req.SessionID == nil && req.PreviousChatID == nil {
-			errorChan <- fmt.Errorf("at least one of assistantId, assistant, sessionId, or previousChatId is required")
-			return
+		var dropped *streamDropError
+		if !errors.As(err, &dropped) {
+			return nil, err
+		}
+		// A stream the server closed cleanly without a done frame is returned
+		// as complete unless it can be resumed, since sending the chat again
+		// would create a second chat
+		if dropped.err == nil && reader.lastEventID == "" {
+			return result, nil
+		}
+		if attempt >= c.streamReconnects || ctx.Err() != nil {
+			if dropped.err == nil {
+				return result, nil
+			}
+			return nil, fmt.Errorf("error reading streaming response: %w", dropped.err)
 		}
 
-		// Validate that sessionId and previousChatId are mutually exclusive
-		if req.SessionID != nil && req.PreviousChatID != nil {
-			errorChan <- fmt.Errorf("sessionId and previousChatId are mutually exclusive")
-			return
+		// The server's retry field takes precedence over the configured delay
+		delay := c.streamReconnectDelay
+		if reader.retry > 0 {
+			delay = reader.retry
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
 		}
+	}
+}
 
-		// Enable streaming
-		streamReq := *req
-		streamReq.Stream = &[]bool{true}[0]
+// streamDropError reports a stream that ended before its done frame. err is
+// the read error, nil when the server closed the connection cleanly.
+type streamDropError struct {
+	err error
+}
 
-		// Marshal request to JSON
-		jsonData, err := json.Marshal(&streamReq)
-		if err != nil {
-			errorChan <- fmt.Errorf("failed to marshal request: %w", err)
-			return
-		}
+// Error implements the error interface
+func (e *streamDropError) Error() string {
+	if e.err == nil {
+		return "stream ended before completion"
+	}
+	return e.err.Error()
+}
 
-		// Create HTTP request
-		url := fmt.Sprintf("%s/chat", c.config.VAPI.BaseURL)
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-		if err != nil {
-			errorChan <- fmt.Errorf("failed to create HTTP request: %w", err)
-			return
-		}
+// streamOnce sends a streaming chat request and hands each frame to onDelta.
+// When reader has seen an event ID it's sent as Last-Event-ID so a server
+// supporting it can resume the stream; reader keeps the event ID and retry
+// delay of the new connection. It returns nil once the stream is done and a
+// *streamDropError when the connection ends early.
+func (c *Client) streamOnce(ctx context.Context, jsonData []byte, reader *sseReader, result *StreamResult, onDelta func(*StreamingChatResponse) error) error {
+	// Create HTTP request
+	url := fmt.Sprintf("%s/chat", c.config.VAPI.BaseURL)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
 
-		// Set headers
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("Authorization", "Bearer "+c.config.VAPI.APIToken)
-		httpReq.Header.Set("Accept", "text/event-stream")
+	// Set headers
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent())
+	token, err := c.config.VAPI.Token(ctx)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if reader.lastEventID != "" {
+		httpReq.Header.Set("Last-Event-ID", reader.lastEventID)
+	}
 
-		// Send request
-		resp, err := c.httpClient.Do(httpReq)
+	// Send request
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check for HTTP errors
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		if err := httputil.CheckJSONResponse(resp, body); err != nil {
+			return err
+		}
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	// Process streaming response
+	reader.scanner = bufio.NewScanner(resp.Body)
+	for {
+		event, err := reader.next()
+		if err == io.EOF {
+			return &streamDropError{}
+		}
 		if err != nil {
-			errorChan <- fmt.Errorf("failed to send request: %w", err)
-			return
+			return &streamDropError{err: err}
 		}
-		defer resp.Body.Close()
 
-		// Check for HTTP errors
-		if resp.StatusCode >= 400 {
-			body, _ := io.ReadAll(resp.Body)
-			errorChan <- fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-			return
+		done, err := c.dispatchStreamEvent(event, result, onDelta)
+		if err != nil || done {
+			return err
 		}
+	}
+}
 
-		// Process streaming response
-		scanner := bufio.NewScanner(resp.Body)
-		for scanner.Scan() {
-			line := scanner.Text()
+// dispatchStreamEvent handles a streamed event, reporting whether it ended
+// the stream. Events with a registered handler go to that handler, "error"
+// events fail the stream and all other events are parsed as chat frames.
+func (c *Client) dispatchStreamEvent(event *StreamEvent, result *StreamResult, onDelta func(*StreamingChatResponse) error) (bool, error) {
+	sentinel := c.streamDoneSentinel
+	if sentinel == "" {
+		sentinel = defaultStreamDoneSentinel
+	}
+	if event.Data == sentinel {
+		return true, nil
+	}
 
-			// Skip empty lines and comments
-			if line == "" || strings.HasPrefix(line, ":") {
-				continue
-			}
+	if handler, ok := c.streamEventHandlers[event.Event]; ok {
+		return false, handler(event)
+	}
+	if event.Event == "error" {
+		return false, fmt.Errorf("stream error: %s", event.Data)
+	}
 
-			// Parse Server-Sent Events format
-			if strings.HasPrefix(line, "data: ") {
-				data := strings.TrimPrefix(line, "data: ")
-
-				// Skip keep-alive messages
-				if data == "" || data == "[DONE]" {
-					continue
-				}
-
-				// Parse JSON data
-				var streamResponse StreamingChatResponse
-				if err := json.Unmarshal([]byte(data), &streamResponse); err != nil {
-					errorChan <- fmt.Errorf("failed to parse streaming response: %w", err)
-					return
-				}
-
-				// Send response to channel
-				select {
-				case responseChan <- &streamResponse:
-				case <-ctx.Done():
-					return
-				}
-
-				// Check if streaming is done
-				if streamResponse.Done {
-					return
-				}
-			}
-		}
+	// Skip keep-alive messages
+	if event.Data == "" {
+		return false, nil
+	}
 
-		if err := scanner.Err(); err != nil {
-			errorChan <- fmt.Errorf("error reading streaming response: %w", err)
-		}
-	}()
+	var streamResponse StreamingChatResponse
+	if err := json.Unmarshal([]byte(event.Data), &streamResponse); err != nil {
+		return false, fmt.Errorf("failed to parse streaming response: %w", err)
+	}
 
-	return responseChan, errorChan
+	result.update(&streamResponse)
+
+	// Hand the frame to the caller
+	if err := onDelta(&streamResponse); err != nil {
+		return false, err
+	}
+
+	return streamResponse.Done, nil
 }
 
 // CreateChatWithText is a convenience method to create a chat with simple text input
@@ -242,6 +441,16 @@ func (c *Client) CreateChatWithAssistant(ctx context.Context, text string, assis
 	return c.CreateChat(ctx, req)
 }
 
+// AskText sends text to an assistant and returns its reply text, the
+// concatenated content of the assistant's output messages
+func (c *Client) AskText(ctx context.Context, text string, assistantID string) (string, error) {
+	response, err := c.CreateChatWithText(ctx, text, &assistantID)
+	if err != nil {
+		return "", err
+	}
+	return response.Text(), nil
+}
+
 // ContinueChat continues a chat from a previous chat ID
 func (c *Client) ContinueChat(ctx context.Context, text string, previousChatID string) (*ChatResponse, error) {
 	req := &CreateChatRequest{
@@ -252,6 +461,12 @@ func (c *Client) ContinueChat(ctx context.Context, text string, previousChatID s
 	return c.CreateChat(ctx, req)
 }
 
+// ContinueChatWithMessages continues a chat from a previous chat ID with a
+// message array as input, e.g. to branch a conversation from edited history
+func (c *Client) ContinueChatWithMessages(ctx context.Context, messages []ChatMessage, previousChatID string) (*ChatResponse, error) {
+	return c.CreateChat(ctx, CreateMessageContinuationRequest(messages, previousChatID))
+}
+
 // CreateSessionChat creates a chat within a session
 func (c *Client) CreateSessionChat(ctx context.Context, text string, sessionID string) (*ChatResponse, error) {
 	req := &CreateChatRequest{
@@ -292,6 +507,10 @@ func (c *Client) ValidateRequest(req *CreateChatRequest) error {
 		return fmt.Errorf("input is required")
 	}
 
+	if err := validateInput(req.Input); err != nil {
+		return err
+	}
+
 	// Validate that at least one of assistantId, assistant, sessionId, or previousChatId is provided
 	if req.AssistantID == nil && req.Assistant == nil && req.SessionID == nil && req.PreviousChatID == nil {
 		return fmt.Errorf("at least one of assistantId, assistant, sessionId, or previousChatId is required")
@@ -310,11 +529,85 @@ func (c *Client) ValidateRequest(req *CreateChatRequest) error {
 	return nil
 }
 
+// validRoles lists the roles accepted in chat message input
+var validRoles = map[string]bool{
+	"system":    true,
+	"user":      true,
+	"assistant": true,
+	"tool":      true,
+}
+
+// validateInput checks that chat input is a non-empty string or a non-empty
+// list of messages with valid roles and content
+func validateInput(input ChatInput) error {
+	switch v := input.(type) {
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("input text cannot be empty")
+		}
+	case []ChatMessage:
+		if len(v) == 0 {
+			return fmt.Errorf("input messages cannot be empty")
+		}
+		for i, msg := range v {
+			if !validRoles[msg.Role] {
+				return fmt.Errorf("input message %d has invalid role %q (must be system, user, assistant, or tool)", i, msg.Role)
+			}
+			if strings.TrimSpace(msg.Content) == "" {
+				return fmt.Errorf("input message %d has empty content", i)
+			}
+		}
+	}
+
+	return nil
+}
+
 // SetTimeout sets a custom timeout for the HTTP client
 func (c *Client) SetTimeout(timeout time.Duration) {
 	c.httpClient.Timeout = timeout
 }
 
+// SetStreamReconnect makes streaming chats reconnect up to attempts times,
+// waiting delay in between, when reading the stream fails before it's done.
+// The last received event ID is sent as Last-Event-ID so the stream can
+// resume where it left off if VAPI supports it; otherwise the chat is sent
+// again and frames may be duplicated. A stream the server closes cleanly
+// without a done frame is only reconnected when it can be resumed. Zero
+// attempts, the default, disables reconnecting.
+func (c *Client) SetStreamReconnect(attempts int, delay time.Duration) {
+	c.streamReconnects = attempts
+	c.streamReconnectDelay = delay
+}
+
+// SetStreamDoneSentinel sets the event data that marks the end of a stream,
+// "[DONE]" by default. An empty sentinel restores the default.
+func (c *Client) SetStreamDoneSentinel(sentinel string) {
+	c.streamDoneSentinel = sentinel
+}
+
+// HandleStreamEvent makes streaming chats pass server-sent events named name
+// to handler instead of parsing them as chat frames. Returning an error from
+// handler ends the stream with that error. A nil handler removes it. Register
+// handlers before streaming; they aren't safe to change concurrently.
+func (c *Client) HandleStreamEvent(name string, handler StreamEventHandler) {
+	if handler == nil {
+		delete(c.streamEventHandlers, name)
+		return
+	}
+	if c.streamEventHandlers == nil {
+		c.streamEventHandlers = make(map[string]StreamEventHandler)
+	}
+	c.streamEventHandlers[name] = handler
+}
+
+// userAgent returns the configured User-Agent, falling back to the default
+func (c *Client) userAgent() string {
+	if c.config.VAPI.UserAgent != "" {
+		return c.config.VAPI.UserAgent
+	}
+	return config.DefaultUserAgent
+}
+
 // GetConfig returns the client configuration
 func (c *Client) GetConfig() *config.Config {
 	return c.config
@@ -326,6 +619,10 @@ func (c *Client) CreateSession(ctx context.Context, assistantID string) (*Sessio
 		return nil, fmt.Errorf("assistantID is required")
 	}
 
+	if err := c.checkAssistant(ctx, &assistantID); err != nil {
+		return nil, err
+	}
+
 	// Create session request payload
 	sessionRequest := map[string]string{
 		"assistantId": assistantID,
@@ -346,7 +643,12 @@ func (c *Client) CreateSession(ctx context.Context, assistantID string) (*Sessio
 
 	// Set headers
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.config.VAPI.APIToken)
+	httpReq.Header.Set("User-Agent", c.userAgent())
+	token, err := c.config.VAPI.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
 
 	// Send request
 	resp, err := c.httpClient.Do(httpReq)
@@ -361,6 +663,11 @@ func (c *Client) CreateSession(ctx context.Context, assistantID string) (*Sessio
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
+	// Reject non-JSON bodies such as HTML error pages from a proxy
+	if err := httputil.CheckJSONResponse(resp, body); err != nil {
+		return nil, err
+	}
+
 	// Check for HTTP errors
 	if resp.StatusCode >= 400 {
 		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
@@ -372,5 +679,6 @@ func (c *Client) CreateSession(ctx context.Context, assistantID string) (*Sessio
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	c.recordSession(sessionResponse.ID)
 	return &sessionResponse, nil
 }
@@ -0,0 +1,88 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/heirloomz/vapi-go-library/pkg/config"
+)
+
+// newTestClient returns a client that sends requests to server
+func newTestClient(server *httptest.Server) *Client {
+	cfg := &config.Config{}
+	cfg.VAPI.BaseURL = server.URL
+	cfg.VAPI.APIToken = "test-token"
+	cfg.VAPI.Timeout = 5 * time.Second
+	return NewClient(cfg)
+}
+
+func TestStreamChatCleanCloseWithoutDoneIsNotResent(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"chat_1\",\"message\":\"Hello\"}\n\n")
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	client.SetStreamReconnect(3, time.Millisecond)
+
+	assistantID := "asst_1"
+	var frames int
+	result, err := client.StreamChatWithResult(context.Background(), &CreateChatRequest{Input: "Hi", AssistantID: &assistantID}, func(*StreamingChatResponse) error {
+		frames++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamChatWithResult: %v", err)
+	}
+
+	if got := posts.Load(); got != 1 {
+		t.Errorf("chat was sent %d times, want 1", got)
+	}
+	if frames != 1 {
+		t.Errorf("received %d frames, want 1", frames)
+	}
+	if result.ChatID != "chat_1" {
+		t.Errorf("ChatID = %q, want %q", result.ChatID, "chat_1")
+	}
+}
+
+func TestStreamChatCleanCloseWithEventIDResumes(t *testing.T) {
+	var posts atomic.Int32
+	var lastEventID atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		if posts.Add(1) == 1 {
+			fmt.Fprint(w, "id: 7\ndata: {\"id\":\"chat_1\",\"message\":\"Hel\"}\n\n")
+			return
+		}
+		lastEventID.Store(r.Header.Get("Last-Event-ID"))
+		fmt.Fprint(w, "id: 8\ndata: {\"id\":\"chat_1\",\"message\":\"lo\",\"done\":true}\n\n")
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	client.SetStreamReconnect(3, time.Millisecond)
+
+	assistantID := "asst_1"
+	err := client.StreamChat(context.Background(), &CreateChatRequest{Input: "Hi", AssistantID: &assistantID}, func(*StreamingChatResponse) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamChat: %v", err)
+	}
+
+	if got := posts.Load(); got != 2 {
+		t.Errorf("chat was sent %d times, want 2", got)
+	}
+	if got, _ := lastEventID.Load().(string); got != "7" {
+		t.Errorf("Last-Event-ID = %q, want %q", got, "7")
+	}
+}
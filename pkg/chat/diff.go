@@ -0,0 +1,136 @@
+package chat
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldDiff describes a field that differs between two assistant configs.
+// Path uses the JSON field names, e.g. "model.temperature" or "endCallPhrases[1]".
+// Old or New is nil when the field is unset on that side.
+type FieldDiff struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// DiffAssistants returns the fields that differ between a and b, sorted by path.
+// Nested structs and maps are compared field by field; slices element by element.
+func DiffAssistants(a, b *Assistant) []FieldDiff {
+	var diffs []FieldDiff
+	diffValues("", reflect.ValueOf(a), reflect.ValueOf(b), &diffs)
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+	return diffs
+}
+
+// diffValues appends the differences between a and b, found under path, to diffs
+func diffValues(path string, a, b reflect.Value, diffs *[]FieldDiff) {
+	if isUnset(a) && isUnset(b) {
+		return
+	}
+	if isUnset(a) || isUnset(b) || a.Type() != b.Type() {
+		*diffs = append(*diffs, FieldDiff{Path: path, Old: diffValue(a), New: diffValue(b)})
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		diffValues(path, a.Elem(), b.Elem(), diffs)
+
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := jsonFieldName(field)
+			if name == "" {
+				continue
+			}
+			diffValues(joinPath(path, name), a.Field(i), b.Field(i), diffs)
+		}
+
+	case reflect.Slice, reflect.Array:
+		length := a.Len()
+		if b.Len() > length {
+			length = b.Len()
+		}
+		for i := 0; i < length; i++ {
+			var elemA, elemB reflect.Value
+			if i < a.Len() {
+				elemA = a.Index(i)
+			}
+			if i < b.Len() {
+				elemB = b.Index(i)
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), elemA, elemB, diffs)
+		}
+
+	case reflect.Map:
+		keys := make(map[string]reflect.Value)
+		for _, key := range a.MapKeys() {
+			keys[fmt.Sprint(key.Interface())] = key
+		}
+		for _, key := range b.MapKeys() {
+			keys[fmt.Sprint(key.Interface())] = key
+		}
+		for name, key := range keys {
+			diffValues(joinPath(path, name), a.MapIndex(key), b.MapIndex(key), diffs)
+		}
+
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*diffs = append(*diffs, FieldDiff{Path: path, Old: a.Interface(), New: b.Interface()})
+		}
+	}
+}
+
+// isUnset returns whether v is missing, a nil pointer, interface, map or slice, or an empty slice or map
+func isUnset(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return false
+}
+
+// diffValue returns the value reported in a FieldDiff, dereferencing pointers
+func diffValue(v reflect.Value) interface{} {
+	if isUnset(v) {
+		return nil
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+// jsonFieldName returns the JSON name of a struct field, or "" when it isn't serialized
+func jsonFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// joinPath appends name to a dotted field path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,37 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// DryRunRequest describes the HTTP request a call would send, without sending it
+type DryRunRequest struct {
+	Method  string          `json:"method"`
+	URL     string          `json:"url"`
+	Headers http.Header     `json:"headers"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// DryRunChat validates a CreateChatRequest and returns the HTTP request that
+// CreateChat would send, without calling the API. The Authorization header
+// is redacted so the result is safe to log or compare against fixtures.
+func (c *Client) DryRunChat(ctx context.Context, req *CreateChatRequest) (*DryRunRequest, error) {
+	httpReq, body, err := c.newChatRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := httpReq.Header.Clone()
+	if headers.Get("Authorization") != "" {
+		headers.Set("Authorization", "Bearer [REDACTED]")
+	}
+
+	return &DryRunRequest{
+		Method:  httpReq.Method,
+		URL:     httpReq.URL.String(),
+		Headers: headers,
+		Body:    body,
+	}, nil
+}
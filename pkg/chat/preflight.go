@@ -0,0 +1,59 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrAssistantNotFound is returned when assistant pre-flight checks are
+// enabled and the requested assistant doesn't exist
+var ErrAssistantNotFound = errors.New("assistant not found")
+
+// SetAssistantPreflight makes CreateChat, the streaming chats and
+// CreateSession check that the requested assistant ID exists before sending
+// the request, returning ErrAssistantNotFound if it doesn't. This costs an
+// extra request per chat, so it's off by default; it suits interactive
+// tooling where a clear error matters more than latency.
+func (c *Client) SetAssistantPreflight(enabled bool) {
+	c.assistantPreflight = enabled
+}
+
+// checkAssistant verifies that the assistant exists when pre-flight checks
+// are enabled
+func (c *Client) checkAssistant(ctx context.Context, assistantID *string) error {
+	if !c.assistantPreflight || assistantID == nil {
+		return nil
+	}
+
+	url := fmt.Sprintf("%s/assistant/%s", c.config.VAPI.BaseURL, *assistantID)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	httpReq.Header.Set("User-Agent", c.userAgent())
+	token, err := c.config.VAPI.Token(ctx)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to check assistant: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrAssistantNotFound, *assistantID)
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to check assistant (status %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package chat
+
+// Ptr returns a pointer to v, for setting optional fields in struct literals
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// StringPtr returns a pointer to s
+func StringPtr(s string) *string {
+	return &s
+}
+
+// BoolPtr returns a pointer to b
+func BoolPtr(b bool) *bool {
+	return &b
+}
+
+// IntPtr returns a pointer to i
+func IntPtr(i int) *int {
+	return &i
+}
+
+// Float64Ptr returns a pointer to f
+func Float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,106 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Resource kinds recorded for chats and sessions
+const (
+	ResourceChat    = "chat"
+	ResourceSession = "session"
+)
+
+// ResourceRecorder records resources created through a client so they can
+// be deleted later, e.g. by voice.ResourceTracker. remove deletes the
+// resource; Forget is called when the resource is deleted by other means.
+type ResourceRecorder interface {
+	Record(kind, id string, remove func(ctx context.Context) error)
+	Forget(kind, id string)
+}
+
+// SetResourceTracker records the chats and sessions created from now on with
+// recorder, so tests can delete everything they created. A nil recorder
+// stops recording.
+func (c *Client) SetResourceTracker(recorder ResourceRecorder) {
+	c.resources = recorder
+}
+
+// recordChat records a created chat when a tracker is set
+func (c *Client) recordChat(chatID string) {
+	if c.resources != nil && chatID != "" {
+		c.resources.Record(ResourceChat, chatID, func(ctx context.Context) error {
+			return c.DeleteChat(ctx, chatID)
+		})
+	}
+}
+
+// recordSession records a created session when a tracker is set
+func (c *Client) recordSession(sessionID string) {
+	if c.resources != nil && sessionID != "" {
+		c.resources.Record(ResourceSession, sessionID, func(ctx context.Context) error {
+			return c.DeleteSession(ctx, sessionID)
+		})
+	}
+}
+
+// forget drops a deleted resource from the tracker
+func (c *Client) forget(kind, id string) {
+	if c.resources != nil {
+		c.resources.Forget(kind, id)
+	}
+}
+
+// DeleteChat deletes a chat
+func (c *Client) DeleteChat(ctx context.Context, chatID string) error {
+	if chatID == "" {
+		return fmt.Errorf("chatID is required")
+	}
+	if err := c.deleteResource(ctx, fmt.Sprintf("%s/chat/%s", c.config.VAPI.BaseURL, chatID)); err != nil {
+		return fmt.Errorf("failed to delete chat: %w", err)
+	}
+	c.forget(ResourceChat, chatID)
+	return nil
+}
+
+// DeleteSession deletes a session
+func (c *Client) DeleteSession(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return fmt.Errorf("sessionID is required")
+	}
+	if err := c.deleteResource(ctx, fmt.Sprintf("%s/session/%s", c.config.VAPI.BaseURL, sessionID)); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	c.forget(ResourceSession, sessionID)
+	return nil
+}
+
+// deleteResource sends a DELETE request to url
+func (c *Client) deleteResource(ctx context.Context, url string) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	httpReq.Header.Set("User-Agent", c.userAgent())
+	token, err := c.config.VAPI.Token(ctx)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SessionManager caches VAPI sessions per user so that returning users reuse
+// their session instead of creating a new one on every request
+type SessionManager struct {
+	client   *Client
+	ttl      time.Duration
+	mu       sync.Mutex
+	sessions map[sessionKey]cachedSession
+	pending  map[sessionKey]*pendingSession
+}
+
+// sessionKey identifies a cached session
+type sessionKey struct {
+	userKey     string
+	assistantID string
+}
+
+// cachedSession is a session along with its expiry time
+type cachedSession struct {
+	session   *SessionResponse
+	expiresAt time.Time
+}
+
+// pendingSession is a session being created. done is closed once session
+// and err are set.
+type pendingSession struct {
+	done    chan struct{}
+	session *SessionResponse
+	err     error
+}
+
+// NewSessionManager creates a new SessionManager that keeps sessions for ttl
+func NewSessionManager(client *Client, ttl time.Duration) *SessionManager {
+	return &SessionManager{
+		client:   client,
+		ttl:      ttl,
+		sessions: make(map[sessionKey]cachedSession),
+		pending:  make(map[sessionKey]*pendingSession),
+	}
+}
+
+// GetOrCreateSession returns the cached session for a user and assistant if it
+// hasn't expired, and creates and caches a new one otherwise. Concurrent calls
+// for the same user and assistant share a single creation.
+func (m *SessionManager) GetOrCreateSession(ctx context.Context, userKey, assistantID string) (*SessionResponse, error) {
+	if userKey == "" {
+		return nil, fmt.Errorf("userKey is required")
+	}
+
+	key := sessionKey{userKey: userKey, assistantID: assistantID}
+
+	for {
+		m.mu.Lock()
+		m.evictExpired(time.Now())
+		if cached, ok := m.sessions[key]; ok {
+			m.mu.Unlock()
+			return cached.session, nil
+		}
+
+		pending, waiting := m.pending[key]
+		if !waiting {
+			pending = &pendingSession{done: make(chan struct{})}
+			m.pending[key] = pending
+		}
+		m.mu.Unlock()
+
+		if !waiting {
+			return m.createSession(ctx, key, pending)
+		}
+
+		select {
+		case <-pending.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if pending.err == nil {
+			return pending.session, nil
+		}
+		// The creation failed, possibly only because its caller gave up, so
+		// try again rather than sharing its error
+	}
+}
+
+// createSession creates the session for a pending entry and caches it, unless
+// the user's sessions were invalidated meanwhile
+func (m *SessionManager) createSession(ctx context.Context, key sessionKey, pending *pendingSession) (*SessionResponse, error) {
+	pending.session, pending.err = m.client.CreateSession(ctx, key.assistantID)
+
+	m.mu.Lock()
+	if m.pending[key] == pending {
+		delete(m.pending, key)
+		if pending.err == nil {
+			m.sessions[key] = cachedSession{
+				session:   pending.session,
+				expiresAt: time.Now().Add(m.ttl),
+			}
+		}
+	}
+	m.mu.Unlock()
+	close(pending.done)
+
+	return pending.session, pending.err
+}
+
+// Invalidate removes all cached sessions for a user
+func (m *SessionManager) Invalidate(userKey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.sessions {
+		if key.userKey == userKey {
+			delete(m.sessions, key)
+		}
+	}
+
+	// Sessions still being created are returned to their callers but not cached
+	for key := range m.pending {
+		if key.userKey == userKey {
+			delete(m.pending, key)
+		}
+	}
+}
+
+// evictExpired removes expired sessions. The caller must hold m.mu.
+func (m *SessionManager) evictExpired(now time.Time) {
+	for key, cached := range m.sessions {
+		if !now.Before(cached.expiresAt) {
+			delete(m.sessions, key)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrCreateSessionConcurrentCallsShareCreation(t *testing.T) {
+	var created atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := created.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"session-%d","assistantId":"assistant-1"}`, n)
+	}))
+	defer server.Close()
+
+	manager := NewSessionManager(newTestClient(server), time.Hour)
+
+	const callers = 10
+	ids := make([]string, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			session, err := manager.GetOrCreateSession(context.Background(), "user-1", "assistant-1")
+			if err != nil {
+				t.Errorf("GetOrCreateSession: %v", err)
+				return
+			}
+			ids[i] = session.ID
+		}(i)
+	}
+	wg.Wait()
+
+	if got := created.Load(); got != 1 {
+		t.Errorf("created %d sessions, want 1", got)
+	}
+	for i, id := range ids {
+		if id != "session-1" {
+			t.Errorf("caller %d got session %q, want session-1", i, id)
+		}
+	}
+}
+
+func TestGetOrCreateSessionRetriesAfterCanceledCreation(t *testing.T) {
+	var created atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := created.Add(1)
+		if n == 1 {
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"session-%d","assistantId":"assistant-1"}`, n)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	manager := NewSessionManager(newTestClient(server), time.Hour)
+
+	// The first caller gives up while its creation is in flight
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := manager.GetOrCreateSession(ctx, "user-1", "assistant-1")
+		first <- err
+	}()
+	for created.Load() == 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	second := make(chan *SessionResponse, 1)
+	go func() {
+		session, err := manager.GetOrCreateSession(context.Background(), "user-1", "assistant-1")
+		if err != nil {
+			t.Errorf("second GetOrCreateSession: %v", err)
+		}
+		second <- session
+	}()
+
+	cancel()
+	if err := <-first; err == nil {
+		t.Fatal("canceled GetOrCreateSession succeeded")
+	}
+
+	// The waiting caller creates its own session instead of sharing the error
+	select {
+	case session := <-second:
+		if session == nil || session.ID != "session-2" {
+			t.Errorf("second caller got %+v, want session-2", session)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("second caller did not get a session")
+	}
+}
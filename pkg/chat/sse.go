@@ -0,0 +1,145 @@
+package chat
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultStreamDoneSentinel is the data payload that ends a stream when none is configured
+const defaultStreamDoneSentinel = "[DONE]"
+
+// StreamEvent is a server-sent event received from a streaming chat
+type StreamEvent struct {
+	// Event is the event name, empty for unnamed events
+	Event string
+	// Data is the event data, with multiple data lines joined by newlines
+	Data string
+	// ID is the last event ID seen on the stream
+	ID string
+	// Retry is the reconnection delay requested by the server, zero if none
+	Retry time.Duration
+}
+
+// StreamEventHandler handles server-sent events of a given name, see HandleStreamEvent
+type StreamEventHandler func(*StreamEvent) error
+
+// sseReader parses a server-sent event stream. Its scanner is replaced on
+// each reconnect while the last event ID and retry delay carry over.
+type sseReader struct {
+	scanner     *bufio.Scanner
+	lastEventID string
+	retry       time.Duration
+}
+
+// next returns the next event. At the end of the stream it returns io.EOF,
+// or the read error if the stream broke. An event left unterminated at the
+// end of the stream is still returned.
+func (r *sseReader) next() (*StreamEvent, error) {
+	var event string
+	var data []string
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+
+		// A blank line dispatches the event; events without data are dropped
+		if line == "" {
+			if data != nil {
+				return r.event(event, data), nil
+			}
+			event = ""
+			continue
+		}
+
+		// Skip comments, used as keep-alives
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				r.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				r.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	if data != nil {
+		return r.event(event, data), nil
+	}
+	return nil, io.EOF
+}
+
+// event builds an event from its accumulated fields
+func (r *sseReader) event(name string, data []string) *StreamEvent {
+	return &StreamEvent{
+		Event: name,
+		Data:  strings.Join(data, "\n"),
+		ID:    r.lastEventID,
+		Retry: r.retry,
+	}
+}
+
+// StreamChatToSSE streams a chat to w as server-sent events, writing one
+// "data:" frame per StreamingChatResponse and flushing after each. Pass the
+// incoming request's context as ctx so the upstream stream stops when the
+// client disconnects.
+//
+// Headers are only written once the first frame arrives, so when the chat
+// fails to start the error is returned and the caller can still respond with
+// an error status. Errors after that are also sent as an "error" event.
+func (c *Client) StreamChatToSSE(ctx context.Context, req *CreateChatRequest, w http.ResponseWriter) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("response writer does not support flushing")
+	}
+
+	headersSent := false
+	err := c.StreamChat(ctx, req, func(delta *StreamingChatResponse) error {
+		if !headersSent {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			w.Header().Set("X-Accel-Buffering", "no")
+			w.WriteHeader(http.StatusOK)
+			headersSent = true
+		}
+
+		data, err := json.Marshal(delta)
+		if err != nil {
+			return fmt.Errorf("failed to marshal streaming response: %w", err)
+		}
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return fmt.Errorf("failed to write event: %w", err)
+		}
+		flusher.Flush()
+		return nil
+	})
+
+	// The client is gone, so there's nobody to report the error to
+	if err != nil && headersSent && ctx.Err() == nil {
+		data, _ := json.Marshal(map[string]string{"error": err.Error()})
+		fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	return err
+}
@@ -1,5 +1,11 @@
 package chat
 
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
 // ChatMessage represents a message in a chat conversation
 type ChatMessage struct {
 	Role             string `json:"role"`
@@ -50,21 +56,150 @@ type ChatResponse struct {
 	Cost           float64       `json:"cost"`
 }
 
+// Text returns the concatenated content of the assistant's output messages
+func (r *ChatResponse) Text() string {
+	var text strings.Builder
+	for _, message := range r.Output {
+		if message.Role == "assistant" {
+			text.WriteString(message.Content)
+		}
+	}
+	return text.String()
+}
+
 // StreamingChatResponse represents a streaming chat response
 type StreamingChatResponse struct {
 	ID      string `json:"id"`
 	OrgID   string `json:"orgId"`
 	Message string `json:"message"`
 	Done    bool   `json:"done"`
-}
 
-// Cost represents the cost breakdown for a chat
+	// Chat metadata, usually only set on the terminal frame
+	SessionID      *string  `json:"sessionId,omitempty"`
+	PreviousChatID *string  `json:"previousChatId,omitempty"`
+	Costs          []Cost   `json:"costs,omitempty"`
+	Cost           *float64 `json:"cost,omitempty"`
+}
+
+// StreamResult holds the final chat metadata of a completed stream, needed to
+// continue the conversation with ContinueChat and to bill it
+type StreamResult struct {
+	ChatID         string
+	OrgID          string
+	SessionID      *string
+	PreviousChatID *string
+	Costs          []Cost
+	Cost           float64
+}
+
+// update records the chat metadata carried by a streamed frame
+func (r *StreamResult) update(frame *StreamingChatResponse) {
+	if frame.ID != "" {
+		r.ChatID = frame.ID
+	}
+	if frame.OrgID != "" {
+		r.OrgID = frame.OrgID
+	}
+	if frame.SessionID != nil {
+		r.SessionID = frame.SessionID
+	}
+	if frame.PreviousChatID != nil {
+		r.PreviousChatID = frame.PreviousChatID
+	}
+	if frame.Costs != nil {
+		r.Costs = frame.Costs
+	}
+	if frame.Cost != nil {
+		r.Cost = *frame.Cost
+	}
+}
+
+// Cost types reported in a cost breakdown
+const (
+	// CostTypeTransport is the telephony or web transport, billed by the minute
+	CostTypeTransport = "transport"
+	// CostTypeTranscriber is speech-to-text, billed by the minute
+	CostTypeTranscriber = "transcriber"
+	// CostTypeModel is the LLM, billed by tokens
+	CostTypeModel = "model"
+	// CostTypeVoice is text-to-speech, billed by characters
+	CostTypeVoice = "voice"
+	// CostTypeVapi is VAPI's platform fee, billed by the minute
+	CostTypeVapi = "vapi"
+	// CostTypeAnalysis is post-call analysis such as the summary or success evaluation
+	CostTypeAnalysis = "analysis"
+	// CostTypeVoicemailDetection is voicemail detection
+	CostTypeVoicemailDetection = "voicemail-detection"
+	// CostTypeKnowledgeBase is knowledge base queries
+	CostTypeKnowledgeBase = "knowledge-base"
+)
+
+// Cost represents one item of the cost breakdown of a chat or call. Which
+// fields are set depends on Type: model and analysis costs have Model and
+// token counts, transcriber costs have Transcriber and Minutes, voice costs
+// have Voice and Characters.
 type Cost struct {
-	Type             string      `json:"type"`
-	Model            interface{} `json:"model"`
-	PromptTokens     int         `json:"promptTokens"`
-	CompletionTokens int         `json:"completionTokens"`
-	Cost             float64     `json:"cost"`
+	Type             string     `json:"type"`
+	Model            *CostModel `json:"model,omitempty"`
+	PromptTokens     int        `json:"promptTokens"`
+	CompletionTokens int        `json:"completionTokens"`
+	Cost             float64    `json:"cost"`
+
+	Transcriber  *CostModel `json:"transcriber,omitempty"`
+	Voice        *CostModel `json:"voice,omitempty"`
+	Provider     string     `json:"provider,omitempty"`
+	Minutes      float64    `json:"minutes,omitempty"`
+	Characters   int        `json:"characters,omitempty"`
+	SubType      string     `json:"subType,omitempty"`
+	AnalysisType string     `json:"analysisType,omitempty"`
+}
+
+// CostModel identifies the model, transcriber or voice a cost was incurred for
+type CostModel struct {
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+	VoiceID  string `json:"voiceId,omitempty"`
+}
+
+// UnmarshalJSON accepts a bare model name as well as an object
+func (m *CostModel) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*m = CostModel{Model: name}
+		return nil
+	}
+
+	type costModel CostModel
+	var model costModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return err
+	}
+	*m = CostModel(model)
+	return nil
+}
+
+// MessageTypes is a list of message type names delivered to the client or server,
+// e.g. "transcript" or "end-of-call-report"
+type MessageTypes []string
+
+// UnmarshalJSON accepts a single message type string as well as an array
+func (m *MessageTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		if single == "" {
+			*m = nil
+		} else {
+			*m = MessageTypes{single}
+		}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*m = list
+	return nil
 }
 
 // Assistant represents the assistant configuration
@@ -76,8 +211,8 @@ type Assistant struct {
 	FirstMessageInterruptionsEnabled *bool                          `json:"firstMessageInterruptionsEnabled,omitempty"`
 	FirstMessageMode                 *string                        `json:"firstMessageMode,omitempty"`
 	VoicemailDetection               *VoicemailDetection            `json:"voicemailDetection,omitempty"`
-	ClientMessages                   *string                        `json:"clientMessages,omitempty"`
-	ServerMessages                   *string                        `json:"serverMessages,omitempty"`
+	ClientMessages                   MessageTypes                   `json:"clientMessages,omitempty"`
+	ServerMessages                   MessageTypes                   `json:"serverMessages,omitempty"`
 	MaxDurationSeconds               *int                           `json:"maxDurationSeconds,omitempty"`
 	BackgroundSound                  *string                        `json:"backgroundSound,omitempty"`
 	BackgroundDenoisingEnabled       *bool                          `json:"backgroundDenoisingEnabled,omitempty"`
@@ -115,8 +250,8 @@ type AssistantOverrides struct {
 	FirstMessageInterruptionsEnabled *bool                          `json:"firstMessageInterruptionsEnabled,omitempty"`
 	FirstMessageMode                 *string                        `json:"firstMessageMode,omitempty"`
 	VoicemailDetection               *VoicemailDetection            `json:"voicemailDetection,omitempty"`
-	ClientMessages                   *string                        `json:"clientMessages,omitempty"`
-	ServerMessages                   *string                        `json:"serverMessages,omitempty"`
+	ClientMessages                   MessageTypes                   `json:"clientMessages,omitempty"`
+	ServerMessages                   MessageTypes                   `json:"serverMessages,omitempty"`
 	MaxDurationSeconds               *int                           `json:"maxDurationSeconds,omitempty"`
 	BackgroundSound                  *string                        `json:"backgroundSound,omitempty"`
 	BackgroundDenoisingEnabled       *bool                          `json:"backgroundDenoisingEnabled,omitempty"`
@@ -146,7 +281,11 @@ type AssistantOverrides struct {
 // Transcriber represents transcriber configuration
 type Transcriber struct {
 	Provider                         string               `json:"provider"`
+	Model                            *string              `json:"model,omitempty"`
 	Language                         *string              `json:"language,omitempty"`
+	SmartFormat                      *bool                `json:"smartFormat,omitempty"`
+	Keywords                         []string             `json:"keywords,omitempty"`
+	Endpointing                      *int                 `json:"endpointing,omitempty"`
 	ConfidenceThreshold              *float64             `json:"confidenceThreshold,omitempty"`
 	EnableUniversalStreamingAPI      *bool                `json:"enableUniversalStreamingApi,omitempty"`
 	FormatTurns                      *bool                `json:"formatTurns,omitempty"`
@@ -201,6 +340,17 @@ type Tool struct {
 	Headers                *Schema                 `json:"headers,omitempty"`
 	BackoffPlan            *BackoffPlan            `json:"backoffPlan,omitempty"`
 	VariableExtractionPlan *VariableExtractionPlan `json:"variableExtractionPlan,omitempty"`
+	Destinations           []TransferDestination   `json:"destinations,omitempty"`
+}
+
+// TransferDestination represents where a transferCall tool can transfer the call
+type TransferDestination struct {
+	Type          string  `json:"type"`
+	Number        *string `json:"number,omitempty"`
+	SipURI        *string `json:"sipUri,omitempty"`
+	AssistantName *string `json:"assistantName,omitempty"`
+	Message       *string `json:"message,omitempty"`
+	Description   *string `json:"description,omitempty"`
 }
 
 // ToolMessage represents a tool message
@@ -246,6 +396,41 @@ type BackoffPlan struct {
 	BaseDelaySeconds *int   `json:"baseDelaySeconds,omitempty"`
 }
 
+// Backoff plan types
+const (
+	BackoffTypeFixed       = "fixed"
+	BackoffTypeExponential = "exponential"
+)
+
+// NextDelay returns the delay before retry attempt (1 for the first retry).
+// Exponential plans double the base delay each attempt; any other type uses a
+// fixed delay. The base delay defaults to one second.
+func (p *BackoffPlan) NextDelay(attempt int) time.Duration {
+	base := time.Second
+	if p.BaseDelaySeconds != nil && *p.BaseDelaySeconds > 0 {
+		base = time.Duration(*p.BaseDelaySeconds) * time.Second
+	}
+
+	if p.Type != BackoffTypeExponential {
+		return base
+	}
+
+	if attempt < 1 {
+		attempt = 1
+	}
+	// Cap the shift so the delay can't overflow
+	if attempt > 20 {
+		attempt = 20
+	}
+	return base << (attempt - 1)
+}
+
+// ShouldRetry reports whether retry attempt (1 for the first retry) is allowed
+// by the plan. Plans without MaxRetries never retry.
+func (p *BackoffPlan) ShouldRetry(attempt int) bool {
+	return p.MaxRetries != nil && attempt <= *p.MaxRetries
+}
+
 // VariableExtractionPlan represents variable extraction configuration
 type VariableExtractionPlan struct {
 	Schema  *Schema         `json:"schema,omitempty"`
@@ -293,7 +478,31 @@ type FormatPlan struct {
 	Enabled              *bool             `json:"enabled,omitempty"`
 	NumberToDigitsCutoff *int              `json:"numberToDigitsCutoff,omitempty"`
 	Replacements         []TextReplacement `json:"replacements,omitempty"`
-	FormattersEnabled    *string           `json:"formattersEnabled,omitempty"`
+	FormattersEnabled    Formatters        `json:"formattersEnabled,omitempty"`
+}
+
+// Formatters is a list of voice formatter names, see the Formatter constants
+type Formatters []string
+
+// UnmarshalJSON accepts a comma-separated string of formatter names as well as an array
+func (f *Formatters) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*f = nil
+		for _, name := range strings.Split(single, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				*f = append(*f, name)
+			}
+		}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*f = list
+	return nil
 }
 
 // TextReplacement represents a text replacement rule
@@ -1,57 +1,262 @@
 package config
 
 import (
+"context"
 "fmt"
+"net"
+"net/http"
 "os"
 "strconv"
+"strings"
+"sync"
 "time"
 
 "gopkg.in/yaml.v3"
 )
 
+// Version is the library version reported in the default User-Agent
+const Version = "0.1.0"
+
+// DefaultUserAgent is the User-Agent sent when none is configured
+const DefaultUserAgent = "vapi-go-library/" + Version
+
+// DefaultMetricsPath is where the webhook server exposes metrics when none is configured
+const DefaultMetricsPath = "/webhooks/metrics"
+
+// Connection pool sizes used when none are configured. Go's own default of 2
+// idle connections per host makes concurrent API calls churn connections.
+const (
+DefaultMaxIdleConns        = 100
+DefaultMaxIdleConnsPerHost = 20
+)
+
 // Config represents the complete VAPI library configuration
 type Config struct {
-VAPI    VAPIConfig    `yaml:"vapi"`
-Tunnel  TunnelConfig  `yaml:"tunnel"`
-Events  EventsConfig  `yaml:"events"`
-Workers WorkersConfig `yaml:"workers"`
+VAPI    VAPIConfig    `yaml:"vapi" json:"vapi"`
+Tunnel  TunnelConfig  `yaml:"tunnel" json:"tunnel"`
+Events  EventsConfig  `yaml:"events" json:"events"`
+Workers WorkersConfig `yaml:"workers" json:"workers"`
+Storage StorageConfig `yaml:"storage" json:"storage"`
 }
 
 // VAPIConfig represents the VAPI API configuration
 type VAPIConfig struct {
-APIToken string        `yaml:"api_token" env:"VAPI_API_TOKEN"`
-BaseURL  string        `yaml:"base_url" env:"VAPI_BASE_URL"`
-Timeout  time.Duration `yaml:"timeout" env:"VAPI_TIMEOUT"`
+APIToken string        `yaml:"api_token" json:"api_token" env:"VAPI_API_TOKEN"`
+BaseURL  string        `yaml:"base_url" json:"base_url" env:"VAPI_BASE_URL"`
+Timeout  time.Duration `yaml:"timeout" json:"timeout" env:"VAPI_TIMEOUT"`
+
+// UserAgent overrides the User-Agent header sent with every request
+UserAgent string `yaml:"user_agent" json:"user_agent" env:"VAPI_USER_AGENT"`
+
+// Debug enables dumping of incoming webhook payloads to the debug directory
+Debug bool `yaml:"debug" json:"debug" env:"VAPI_DEBUG"`
+
+// CaptureRawResponses keeps the raw body of the last API response for
+// LastRawResponse. It copies every response, so leave it off in production.
+CaptureRawResponses bool `yaml:"capture_raw_responses" json:"capture_raw_responses" env:"VAPI_CAPTURE_RAW_RESPONSES"`
+
+// Transport tunes individual phases of a request; Timeout still bounds the whole request
+Transport TransportConfig `yaml:"transport" json:"transport"`
+
+// MaxResponseBytes caps the size of a decoded API response; zero uses the
+// client default and a negative value disables the limit
+MaxResponseBytes int64 `yaml:"max_response_bytes" json:"max_response_bytes" env:"VAPI_MAX_RESPONSE_BYTES"`
+
+// TokenProvider, when set, supplies the API token for each request and
+// takes precedence over APIToken
+TokenProvider TokenProvider `yaml:"-" json:"-"`
+
+// HeaderProvider, when set, supplies extra headers for each request from
+// its context, e.g. to propagate a trace ID
+HeaderProvider HeaderProvider `yaml:"-" json:"-"`
+}
+
+// HeaderProvider returns headers to add to a request made with ctx. Headers
+// the library sets itself, such as Authorization, aren't overridden.
+type HeaderProvider func(ctx context.Context) map[string]string
+
+// ContextValueHeader returns a HeaderProvider that sends the string stored
+// in the context under key as header, e.g. a trace ID as "X-Request-ID"
+func ContextValueHeader(key interface{}, header string) HeaderProvider {
+return func(ctx context.Context) map[string]string {
+value, ok := ctx.Value(key).(string)
+if !ok || value == "" {
+return nil
+}
+return map[string]string{header: value}
+}
+}
+
+// CombineHeaderProviders returns a HeaderProvider merging the headers of
+// providers, with later providers taking precedence
+func CombineHeaderProviders(providers ...HeaderProvider) HeaderProvider {
+return func(ctx context.Context) map[string]string {
+headers := make(map[string]string)
+for _, provider := range providers {
+for key, value := range provider(ctx) {
+headers[key] = value
+}
+}
+return headers
+}
+}
+
+// TokenProvider returns a VAPI API token for a request
+type TokenProvider func(ctx context.Context) (string, error)
+
+// Token returns the API token to use for a request
+func (c *VAPIConfig) Token(ctx context.Context) (string, error) {
+if c.TokenProvider == nil {
+return c.APIToken, nil
+}
+
+token, err := c.TokenProvider(ctx)
+if err != nil {
+return "", fmt.Errorf("failed to obtain API token: %w", err)
+}
+return token, nil
+}
+
+// CachedTokenProvider wraps a TokenProvider so that a token is reused for ttl
+// before the underlying provider is called again
+func CachedTokenProvider(provider TokenProvider, ttl time.Duration) TokenProvider {
+var (
+mu      sync.Mutex
+token   string
+expires time.Time
+)
+
+return func(ctx context.Context) (string, error) {
+mu.Lock()
+defer mu.Unlock()
+
+if token != "" && time.Now().Before(expires) {
+return token, nil
+}
+
+fresh, err := provider(ctx)
+if err != nil {
+return "", err
+}
+
+token = fresh
+expires = time.Now().Add(ttl)
+return token, nil
+}
 }
 
 // TunnelConfig represents the tunnel configuration
 type TunnelConfig struct {
-Provider  string `yaml:"provider" env:"TUNNEL_PROVIDER"`
-AuthToken string `yaml:"auth_token" env:"NGROK_AUTH_TOKEN"`
-Port      int    `yaml:"port" env:"TUNNEL_PORT"`
-Subdomain string `yaml:"subdomain" env:"TUNNEL_SUBDOMAIN"`
+Provider  string `yaml:"provider" json:"provider" env:"TUNNEL_PROVIDER"`
+AuthToken string `yaml:"auth_token" json:"auth_token" env:"NGROK_AUTH_TOKEN"`
+Port      int    `yaml:"port" json:"port" env:"TUNNEL_PORT"`
+Subdomain string `yaml:"subdomain" json:"subdomain" env:"TUNNEL_SUBDOMAIN"`
+
+// AllowedCIDRs, when set, restricts webhooks to these source networks
+AllowedCIDRs []string `yaml:"allowed_cidrs" json:"allowed_cidrs" env:"WEBHOOK_ALLOWED_CIDRS"`
+// TrustForwardedFor takes the webhook source from X-Forwarded-For; only
+// enable it behind a proxy that sets that header
+TrustForwardedFor bool `yaml:"trust_forwarded_for" json:"trust_forwarded_for" env:"WEBHOOK_TRUST_FORWARDED_FOR"`
+
+// MetricsEnabled exposes the webhook counters in Prometheus text format at MetricsPath
+MetricsEnabled bool   `yaml:"metrics_enabled" json:"metrics_enabled" env:"WEBHOOK_METRICS_ENABLED"`
+MetricsPath    string `yaml:"metrics_path" json:"metrics_path" env:"WEBHOOK_METRICS_PATH"`
+
+// FailureLogPath, when set, is a file the raw payloads of failed webhooks are
+// appended to as JSON lines; it's rotated at FailureLogMaxBytes (10 MiB if zero)
+FailureLogPath     string `yaml:"failure_log_path" json:"failure_log_path" env:"WEBHOOK_FAILURE_LOG"`
+FailureLogMaxBytes int64  `yaml:"failure_log_max_bytes" json:"failure_log_max_bytes" env:"WEBHOOK_FAILURE_LOG_MAX_BYTES"`
 }
 
 // EventsConfig represents the events system configuration
 type EventsConfig struct {
-Backend string      `yaml:"backend" env:"EVENTS_BACKEND"`
-Redis   RedisConfig `yaml:"redis"`
+Backend         string      `yaml:"backend" json:"backend" env:"EVENTS_BACKEND"`
+OrderedDelivery bool        `yaml:"ordered_delivery" json:"ordered_delivery" env:"EVENTS_ORDERED_DELIVERY"`
+Redis           RedisConfig `yaml:"redis" json:"redis"`
 }
 
 // RedisConfig represents the Redis configuration
 type RedisConfig struct {
-Host     string `yaml:"host" env:"REDIS_HOST"`
-Port     int    `yaml:"port" env:"REDIS_PORT"`
-DB       int    `yaml:"db" env:"REDIS_DB"`
-Password string `yaml:"password" env:"REDIS_PASSWORD"`
+Host     string `yaml:"host" json:"host" env:"REDIS_HOST"`
+Port     int    `yaml:"port" json:"port" env:"REDIS_PORT"`
+DB       int    `yaml:"db" json:"db" env:"REDIS_DB"`
+Password string `yaml:"password" json:"password" env:"REDIS_PASSWORD"`
+}
+
+// StorageConfig represents the local storage directories. An empty directory
+// disables the feature that uses it and no directory is created for it.
+type StorageConfig struct {
+StorageDir string `yaml:"storage_dir" json:"storage_dir" env:"VAPI_STORAGE_DIR"`
+CacheDir   string `yaml:"cache_dir" json:"cache_dir" env:"VAPI_CACHE_DIR"`
+DebugDir   string `yaml:"debug_dir" json:"debug_dir" env:"VAPI_DEBUG_DIR"`
+}
+
+// TransportConfig represents granular HTTP transport timeouts and connection
+// pooling. Zero timeouts keep Go's defaults; zero pool sizes use the library's.
+type TransportConfig struct {
+DialTimeout           time.Duration `yaml:"dial_timeout" json:"dial_timeout" env:"VAPI_DIAL_TIMEOUT"`
+TLSHandshakeTimeout   time.Duration `yaml:"tls_handshake_timeout" json:"tls_handshake_timeout" env:"VAPI_TLS_HANDSHAKE_TIMEOUT"`
+ResponseHeaderTimeout time.Duration `yaml:"response_header_timeout" json:"response_header_timeout" env:"VAPI_RESPONSE_HEADER_TIMEOUT"`
+IdleConnTimeout       time.Duration `yaml:"idle_conn_timeout" json:"idle_conn_timeout" env:"VAPI_IDLE_CONN_TIMEOUT"`
+
+MaxIdleConns        int `yaml:"max_idle_conns" json:"max_idle_conns" env:"VAPI_MAX_IDLE_CONNS"`
+MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host" json:"max_idle_conns_per_host" env:"VAPI_MAX_IDLE_CONNS_PER_HOST"`
+// MaxConnsPerHost limits connections per host, including in-use ones; zero means no limit
+MaxConnsPerHost int `yaml:"max_conns_per_host" json:"max_conns_per_host" env:"VAPI_MAX_CONNS_PER_HOST"`
+}
+
+// NewTransport builds an HTTP transport with the configured timeouts and
+// connection pool sizes, based on Go's default transport
+func (t TransportConfig) NewTransport() *http.Transport {
+transport := http.DefaultTransport.(*http.Transport).Clone()
+transport.MaxIdleConns = DefaultMaxIdleConns
+transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+if t.MaxIdleConns > 0 {
+transport.MaxIdleConns = t.MaxIdleConns
+}
+if t.MaxIdleConnsPerHost > 0 {
+transport.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+}
+if t.MaxConnsPerHost > 0 {
+transport.MaxConnsPerHost = t.MaxConnsPerHost
+}
+if t.DialTimeout > 0 {
+transport.DialContext = (&net.Dialer{
+Timeout:   t.DialTimeout,
+KeepAlive: 30 * time.Second,
+}).DialContext
+}
+if t.TLSHandshakeTimeout > 0 {
+transport.TLSHandshakeTimeout = t.TLSHandshakeTimeout
+}
+if t.ResponseHeaderTimeout > 0 {
+transport.ResponseHeaderTimeout = t.ResponseHeaderTimeout
+}
+if t.IdleConnTimeout > 0 {
+transport.IdleConnTimeout = t.IdleConnTimeout
+}
+return transport
 }
 
+// Worker pool defaults
+const (
+DefaultWorkerCount         = 3
+DefaultWorkerQueueSize     = 100
+DefaultWorkerRetryAttempts = 3
+DefaultWorkerRetryDelay    = 5 * time.Second
+)
+
 // WorkersConfig represents the worker pool configuration
 type WorkersConfig struct {
-Count         int           `yaml:"count" env:"WORKERS_COUNT"`
-QueueSize     int           `yaml:"queue_size" env:"WORKERS_QUEUE_SIZE"`
-RetryAttempts int           `yaml:"retry_attempts" env:"WORKERS_RETRY_ATTEMPTS"`
-RetryDelay    time.Duration `yaml:"retry_delay" env:"WORKERS_RETRY_DELAY"`
+Count         int           `yaml:"count" json:"count" env:"WORKERS_COUNT"`
+QueueSize     int           `yaml:"queue_size" json:"queue_size" env:"WORKERS_QUEUE_SIZE"`
+RetryAttempts int           `yaml:"retry_attempts" json:"retry_attempts" env:"WORKERS_RETRY_ATTEMPTS"`
+RetryDelay    time.Duration `yaml:"retry_delay" json:"retry_delay" env:"WORKERS_RETRY_DELAY"`
+
+// HandlerRetries is how often the event bus calls a handler again after it
+// returns an error, waiting RetryDelay in between. Zero, the default,
+// disables handler retries, so handlers must opt in to being run again.
+HandlerRetries int `yaml:"handler_retries" json:"handler_retries" env:"WORKERS_HANDLER_RETRIES"`
 }
 
 // LoadFromFile loads configuration from a YAML file
@@ -82,15 +287,35 @@ VAPI: VAPIConfig{
 APIToken: getEnv("VAPI_API_TOKEN", ""),
 BaseURL:  getEnv("VAPI_BASE_URL", "https://api.vapi.ai"),
 Timeout:  parseDuration(getEnv("VAPI_TIMEOUT", "30s")),
+UserAgent: getEnv("VAPI_USER_AGENT", DefaultUserAgent),
+Debug:     parseBool(getEnv("VAPI_DEBUG", "false")),
+CaptureRawResponses: parseBool(getEnv("VAPI_CAPTURE_RAW_RESPONSES", "false")),
+MaxResponseBytes: int64(parseInt(getEnv("VAPI_MAX_RESPONSE_BYTES", "0"))),
+Transport: TransportConfig{
+DialTimeout:           parseDuration(getEnv("VAPI_DIAL_TIMEOUT", "")),
+TLSHandshakeTimeout:   parseDuration(getEnv("VAPI_TLS_HANDSHAKE_TIMEOUT", "")),
+ResponseHeaderTimeout: parseDuration(getEnv("VAPI_RESPONSE_HEADER_TIMEOUT", "")),
+IdleConnTimeout:       parseDuration(getEnv("VAPI_IDLE_CONN_TIMEOUT", "")),
+MaxIdleConns:          parseInt(getEnv("VAPI_MAX_IDLE_CONNS", "0")),
+MaxIdleConnsPerHost:   parseInt(getEnv("VAPI_MAX_IDLE_CONNS_PER_HOST", "0")),
+MaxConnsPerHost:       parseInt(getEnv("VAPI_MAX_CONNS_PER_HOST", "0")),
+},
 },
 Tunnel: TunnelConfig{
 Provider:  getEnv("TUNNEL_PROVIDER", "ngrok"),
 AuthToken: getEnv("NGROK_AUTH_TOKEN", ""),
 Port:      parseInt(getEnv("TUNNEL_PORT", "8080")),
 Subdomain: getEnv("TUNNEL_SUBDOMAIN", ""),
+AllowedCIDRs:      parseList(getEnv("WEBHOOK_ALLOWED_CIDRS", "")),
+TrustForwardedFor: parseBool(getEnv("WEBHOOK_TRUST_FORWARDED_FOR", "false")),
+MetricsEnabled:    parseBool(getEnv("WEBHOOK_METRICS_ENABLED", "false")),
+MetricsPath:       getEnv("WEBHOOK_METRICS_PATH", DefaultMetricsPath),
+FailureLogPath:     getEnv("WEBHOOK_FAILURE_LOG", ""),
+FailureLogMaxBytes: int64(parseInt(getEnv("WEBHOOK_FAILURE_LOG_MAX_BYTES", "0"))),
 },
 Events: EventsConfig{
-Backend: getEnv("EVENTS_BACKEND", "redis"),
+Backend:         getEnv("EVENTS_BACKEND", "redis"),
+OrderedDelivery: parseBool(getEnv("EVENTS_ORDERED_DELIVERY", "false")),
 Redis: RedisConfig{
 Host:     getEnv("REDIS_HOST", "localhost"),
 Port:     parseInt(getEnv("REDIS_PORT", "6379")),
@@ -103,6 +328,13 @@ Count:         parseInt(getEnv("WORKERS_COUNT", "3")),
 QueueSize:     parseInt(getEnv("WORKERS_QUEUE_SIZE", "100")),
 RetryAttempts: parseInt(getEnv("WORKERS_RETRY_ATTEMPTS", "3")),
 RetryDelay:    parseDuration(getEnv("WORKERS_RETRY_DELAY", "5s")),
+
+HandlerRetries: parseInt(getEnv("WORKERS_HANDLER_RETRIES", "0")),
+},
+Storage: StorageConfig{
+StorageDir: getEnv("VAPI_STORAGE_DIR", ""),
+CacheDir:   getEnv("VAPI_CACHE_DIR", ""),
+DebugDir:   getEnv("VAPI_DEBUG_DIR", ""),
 },
 }
 
@@ -118,32 +350,42 @@ c.VAPI.BaseURL = "https://api.vapi.ai"
 if c.VAPI.Timeout == 0 {
 c.VAPI.Timeout = 30 * time.Second
 }
+if c.VAPI.UserAgent == "" {
+c.VAPI.UserAgent = DefaultUserAgent
+}
 if c.Tunnel.Provider == "" {
 c.Tunnel.Provider = "ngrok"
 }
 if c.Tunnel.Port == 0 {
 c.Tunnel.Port = 8080
 }
+if c.Tunnel.MetricsPath == "" {
+c.Tunnel.MetricsPath = DefaultMetricsPath
+}
 if c.Events.Backend == "" {
 c.Events.Backend = "redis"
 }
+// Redis defaults only matter when the Redis backend is in use;
+// "none" disables the event bus entirely
+if c.Events.Backend == "redis" {
 if c.Events.Redis.Host == "" {
 c.Events.Redis.Host = "localhost"
 }
 if c.Events.Redis.Port == 0 {
 c.Events.Redis.Port = 6379
 }
+}
 if c.Workers.Count == 0 {
-c.Workers.Count = 3
+c.Workers.Count = DefaultWorkerCount
 }
 if c.Workers.QueueSize == 0 {
-c.Workers.QueueSize = 100
+c.Workers.QueueSize = DefaultWorkerQueueSize
 }
 if c.Workers.RetryAttempts == 0 {
-c.Workers.RetryAttempts = 3
+c.Workers.RetryAttempts = DefaultWorkerRetryAttempts
 }
 if c.Workers.RetryDelay == 0 {
-c.Workers.RetryDelay = 5 * time.Second
+c.Workers.RetryDelay = DefaultWorkerRetryDelay
 }
 }
 
@@ -162,6 +404,23 @@ return i
 return 0
 }
 
+func parseList(s string) []string {
+var items []string
+for _, item := range strings.Split(s, ",") {
+if item = strings.TrimSpace(item); item != "" {
+items = append(items, item)
+}
+}
+return items
+}
+
+func parseBool(s string) bool {
+if b, err := strconv.ParseBool(s); err == nil {
+return b
+}
+return false
+}
+
 func parseDuration(s string) time.Duration {
 if d, err := time.ParseDuration(s); err == nil {
 return d
@@ -0,0 +1,19 @@
+package config
+
+import "testing"
+
+func TestLoadFromEnvHandlerRetriesOptIn(t *testing.T) {
+	t.Setenv("WORKERS_RETRY_ATTEMPTS", "")
+	t.Setenv("WORKERS_HANDLER_RETRIES", "")
+
+	cfg := LoadFromEnv()
+	if cfg.Workers.HandlerRetries != 0 {
+		t.Errorf("HandlerRetries = %d by default, want 0", cfg.Workers.HandlerRetries)
+	}
+
+	t.Setenv("WORKERS_HANDLER_RETRIES", "2")
+	cfg = LoadFromEnv()
+	if cfg.Workers.HandlerRetries != 2 {
+		t.Errorf("HandlerRetries = %d, want 2", cfg.Workers.HandlerRetries)
+	}
+}
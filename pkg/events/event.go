@@ -2,17 +2,22 @@ package events
 
 import (
 "encoding/json"
+"fmt"
 "time"
 )
 
 // Event represents a generic event in the VAPI library
 type Event struct {
-ID        string                 `json:"id"`
-Type      string                 `json:"type"`
-Timestamp time.Time              `json:"timestamp"`
-Source    string                 `json:"source"`
-Data      interface{}            `json:"data"`
-Metadata  map[string]interface{} `json:"metadata"`
+ID           string                 `json:"id"`
+Type         string                 `json:"type"`
+Timestamp    time.Time              `json:"timestamp"`
+Source       string                 `json:"source"`
+Data         interface{}            `json:"data"`
+Metadata     map[string]interface{} `json:"metadata"`
+PartitionKey string                 `json:"partitionKey,omitempty"` // Groups related events (e.g. by call ID) for ordered delivery
+// SequenceNumber is assigned by the bus on publish and increases by one for
+// each event it publishes, so handlers can detect gaps or reordering
+SequenceNumber uint64 `json:"sequenceNumber,omitempty"`
 }
 
 // Event types constants
@@ -24,6 +29,12 @@ EventAssistantUpdated  = "vapi.assistant.updated"
 EventFileUploaded      = "vapi.file.uploaded"
 EventToolCreated       = "vapi.tool.created"
 EventWebhookReceived   = "vapi.webhook.received"
+EventCallStatusUpdate  = "vapi.call.status_update"
+EventCallHang          = "vapi.call.hang"
+EventEndOfCallReport   = "vapi.call.end_of_call_report"
+EventToolCalls         = "vapi.tool.calls"
+EventAssistantRequest  = "vapi.assistant.request"
+EventSpeechUpdate      = "vapi.speech.update"
 )
 
 // NewEvent creates a new event with the given parameters
@@ -67,9 +78,67 @@ value, exists := e.Metadata[key]
 return value, exists
 }
 
-// generateEventID generates a unique event ID
+// Metadata keys describing delivery attempts of a retried event
+const (
+MetadataAttempt     = "attempt"
+MetadataMaxAttempts = "maxAttempts"
+MetadataFirstSeenAt = "firstSeenAt"
+)
+
+// SetAttempt records which processing attempt this is. The Redis bus sets it
+// on every delivery when handler retries are enabled. Attempts are numbered from 1.
+func (e *Event) SetAttempt(attempt, maxAttempts int, firstSeenAt time.Time) {
+e.AddMetadata(MetadataAttempt, attempt)
+e.AddMetadata(MetadataMaxAttempts, maxAttempts)
+e.AddMetadata(MetadataFirstSeenAt, firstSeenAt.UTC().Format(time.RFC3339Nano))
+}
+
+// Attempt returns the processing attempt recorded by SetAttempt, or 1 if none was recorded.
+// After a Redis round trip the number is decoded as float64, which is handled here.
+func (e *Event) Attempt() int {
+value, ok := e.GetMetadata(MetadataAttempt)
+if !ok {
+return 1
+}
+switch attempt := value.(type) {
+case int:
+return attempt
+case float64:
+return int(attempt)
+default:
+return 1
+}
+}
+
+// IsRetry returns whether the event is being processed again after a failed attempt
+func (e *Event) IsRetry() bool {
+return e.Attempt() > 1
+}
+
+// DecodeData decodes the event data into T. Data published in-process is
+// returned as-is, while data that went through the Redis bus arrives as a
+// generic map and is re-encoded into T.
+func DecodeData[T any](e *Event) (*T, error) {
+switch data := e.Data.(type) {
+case *T:
+return data, nil
+case T:
+return &data, nil
+}
+
+raw, err := json.Marshal(e.Data)
+if err != nil {
+return nil, fmt.Errorf("failed to encode %s event data: %w", e.Type, err)
+}
+
+var data T
+if err := json.Unmarshal(raw, &data); err != nil {
+return nil, fmt.Errorf("failed to decode %s event data: %w", e.Type, err)
+}
+return &data, nil
+}
+
+// generateEventID generates a unique, lexicographically sortable event ID (ULID)
 func generateEventID() string {
-// Simple timestamp-based ID for now
-// In production, you might want to use UUID
-return time.Now().Format("20060102150405.000000")
+return eventIDs.next(time.Now())
 }
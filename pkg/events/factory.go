@@ -2,6 +2,7 @@ package events
 
 import (
 	"fmt"
+	"time"
 )
 
 // NewEventBus creates a new event bus based on the backend type
@@ -9,14 +10,22 @@ func NewEventBus(backend string, config interface{}) (EventBus, error) {
 	switch backend {
 	case "redis":
 		if redisConfig, ok := config.(RedisConfig); ok {
-			return NewRedisEventBus(
+			bus, err := NewRedisEventBus(
 				redisConfig.Host,
 				redisConfig.Port,
 				redisConfig.Password,
 				redisConfig.DB,
 			)
+			if err != nil {
+				return nil, err
+			}
+			bus.SetOrderedDelivery(redisConfig.OrderedDelivery)
+			bus.SetHandlerRetry(redisConfig.HandlerRetries, redisConfig.HandlerRetryDelay)
+			return bus, nil
 		}
 		return nil, fmt.Errorf("invalid Redis configuration")
+	case "none":
+		return NewNoopEventBus(), nil
 	default:
 		return nil, fmt.Errorf("unsupported event bus backend: %s", backend)
 	}
@@ -28,4 +37,13 @@ type RedisConfig struct {
 	Port     int
 	Password string
 	DB       int
+
+	// OrderedDelivery serializes handling of events that share a PartitionKey,
+	// across event types.
+	OrderedDelivery bool
+
+	// HandlerRetries is how often a handler that returned an error is called
+	// again, waiting HandlerRetryDelay in between
+	HandlerRetries    int
+	HandlerRetryDelay time.Duration
 }
@@ -0,0 +1,221 @@
+package events
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a minimal Redis server speaking enough RESP2 for pub/sub:
+// PING, SUBSCRIBE, UNSUBSCRIBE and PUBLISH. Other commands reply OK.
+type fakeRedis struct {
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[*fakeRedisConn]bool
+}
+
+// fakeRedisConn is a client connection and the channels it's subscribed to
+type fakeRedisConn struct {
+	net.Conn
+	writeMu  sync.Mutex
+	channels map[string]bool
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &fakeRedis{listener: listener, conns: make(map[*fakeRedisConn]bool)}
+	go s.accept()
+	t.Cleanup(func() {
+		listener.Close()
+		s.dropClients()
+	})
+	return s
+}
+
+// newBus returns a Redis event bus connected to the server
+func (s *fakeRedis) newBus(t *testing.T) *RedisEventBus {
+	t.Helper()
+
+	addr := s.listener.Addr().(*net.TCPAddr)
+	bus, err := NewRedisEventBus(addr.IP.String(), addr.Port, "", 0)
+	if err != nil {
+		t.Fatalf("NewRedisEventBus: %v", err)
+	}
+	t.Cleanup(func() { bus.Stop() })
+	return bus
+}
+
+func (s *fakeRedis) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &fakeRedisConn{Conn: conn, channels: make(map[string]bool)}
+		s.mu.Lock()
+		s.conns[c] = true
+		s.mu.Unlock()
+		go s.serve(c)
+	}
+}
+
+func (s *fakeRedis) serve(c *fakeRedisConn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		c.Close()
+	}()
+
+	reader := bufio.NewReader(c)
+	for {
+		args, err := readFakeRedisCommand(reader)
+		if err != nil {
+			return
+		}
+		s.handle(c, args)
+	}
+}
+
+func (s *fakeRedis) handle(c *fakeRedisConn, args []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "HELLO":
+		c.write("-ERR unknown command 'HELLO'\r\n")
+	case "PING":
+		if len(c.channels) > 0 {
+			c.write("*2\r\n$4\r\npong\r\n$0\r\n\r\n")
+		} else {
+			c.write("+PONG\r\n")
+		}
+	case "SUBSCRIBE":
+		for _, channel := range args[1:] {
+			c.channels[channel] = true
+			c.write(fmt.Sprintf("*3\r\n%s%s:%d\r\n", bulk("subscribe"), bulk(channel), len(c.channels)))
+		}
+	case "UNSUBSCRIBE":
+		channels := args[1:]
+		if len(channels) == 0 {
+			for channel := range c.channels {
+				channels = append(channels, channel)
+			}
+		}
+		for _, channel := range channels {
+			delete(c.channels, channel)
+			c.write(fmt.Sprintf("*3\r\n%s%s:%d\r\n", bulk("unsubscribe"), bulk(channel), len(c.channels)))
+		}
+	case "PUBLISH":
+		receivers := 0
+		for conn := range s.conns {
+			if conn.channels[args[1]] {
+				conn.write(fmt.Sprintf("*3\r\n%s%s%s", bulk("message"), bulk(args[1]), bulk(args[2])))
+				receivers++
+			}
+		}
+		c.write(fmt.Sprintf(":%d\r\n", receivers))
+	default:
+		c.write("+OK\r\n")
+	}
+}
+
+// subscribers returns how many connections are subscribed to channel
+func (s *fakeRedis) subscribers(channel string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for conn := range s.conns {
+		if conn.channels[channel] {
+			count++
+		}
+	}
+	return count
+}
+
+// connections returns the number of open client connections
+func (s *fakeRedis) connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// dropClients closes every client connection, as a Redis restart would
+func (s *fakeRedis) dropClients() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (c *fakeRedisConn) write(reply string) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	io.WriteString(c, reply)
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+// readFakeRedisCommand reads a command sent as a RESP array of bulk strings
+func readFakeRedisCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected command line %q", line)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args = append(args, string(data[:size]))
+	}
+	return args, nil
+}
+
+// waitFor polls condition until it holds or the timeout passes
+func waitFor(t *testing.T, timeout time.Duration, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,89 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// crockfordAlphabet is the Crockford base32 alphabet used by ULIDs
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidGenerator produces monotonic ULIDs: IDs generated within the same
+// millisecond increment the random component, so they always sort in
+// generation order
+type ulidGenerator struct {
+	mu       sync.Mutex
+	lastTime uint64
+	lastRand [10]byte
+}
+
+var eventIDs = &ulidGenerator{}
+
+// next returns a new ULID for time t
+func (g *ulidGenerator) next(t time.Time) string {
+	ms := uint64(t.UnixMilli())
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if ms <= g.lastTime {
+		// Same millisecond (or the clock went backwards): stay on the last
+		// timestamp and increment the random component
+		ms = g.lastTime
+		if !incrementBytes(g.lastRand[:]) {
+			ms++
+			g.lastTime = ms
+		}
+	} else {
+		if _, err := rand.Read(g.lastRand[:]); err != nil {
+			// Fall back to a zero random component; ordering still holds
+			g.lastRand = [10]byte{}
+		}
+		g.lastTime = ms
+	}
+
+	var id [16]byte
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+	copy(id[6:], g.lastRand[:])
+
+	return encodeULID(id)
+}
+
+// incrementBytes adds one to a big-endian byte slice, reporting false on overflow
+func incrementBytes(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID encodes 128 bits as 26 Crockford base32 characters
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[0:8])
+	lo := binary.BigEndian.Uint64(id[8:16])
+
+	out := make([]byte, 26)
+	for i := range out {
+		shift := uint(125 - 5*i)
+
+		var value uint64
+		switch {
+		case shift >= 64:
+			value = hi >> (shift - 64)
+		case shift+5 <= 64:
+			value = lo >> shift
+		default:
+			value = hi<<(64-shift) | lo>>shift
+		}
+
+		out[i] = crockfordAlphabet[value&0x1f]
+	}
+
+	return string(out)
+}
@@ -0,0 +1,131 @@
+package events
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestULIDGeneratorConcurrentSameMillisecond(t *testing.T) {
+	const (
+		goroutines   = 16
+		perGoroutine = 500
+	)
+
+	gen := &ulidGenerator{}
+	now := time.UnixMilli(1760611200000)
+
+	ids := make([][]string, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perGoroutine; j++ {
+				ids[i] = append(ids[i], gen.next(now))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, goroutines*perGoroutine)
+	var all []string
+	for i, generated := range ids {
+		for j, id := range generated {
+			if len(id) != 26 {
+				t.Fatalf("ID %q has length %d, want 26", id, len(id))
+			}
+			if seen[id] {
+				t.Fatalf("duplicate ID %q", id)
+			}
+			seen[id] = true
+
+			// Each goroutine's IDs were generated in order, so they must sort in order
+			if j > 0 && id <= generated[j-1] {
+				t.Fatalf("goroutine %d: ID %q sorts before the previously generated %q", i, id, generated[j-1])
+			}
+		}
+		all = append(all, generated...)
+	}
+
+	// Every ID shares the millisecond, so sorting keeps them under one timestamp prefix
+	sort.Strings(all)
+	if all[0][:10] != all[len(all)-1][:10] {
+		t.Errorf("IDs span timestamps %s..%s, want a single millisecond", all[0][:10], all[len(all)-1][:10])
+	}
+}
+
+func TestULIDGeneratorSortsInGenerationOrder(t *testing.T) {
+	gen := &ulidGenerator{}
+	base := time.UnixMilli(1760611200000)
+
+	// Same millisecond, later milliseconds, and a clock that steps backwards
+	times := []time.Time{
+		base,
+		base,
+		base.Add(time.Millisecond),
+		base.Add(time.Millisecond),
+		base,
+		base.Add(5 * time.Millisecond),
+	}
+
+	var generated []string
+	for _, at := range times {
+		generated = append(generated, gen.next(at))
+	}
+
+	sorted := append([]string(nil), generated...)
+	sort.Strings(sorted)
+	for i := range generated {
+		if sorted[i] != generated[i] {
+			t.Fatalf("sorted IDs %v differ from generation order %v", sorted, generated)
+		}
+	}
+}
+
+func TestIncrementBytesOverflow(t *testing.T) {
+	b := []byte{0xff, 0xff}
+	if incrementBytes(b) {
+		t.Error("incrementBytes reported no overflow for all-ones input")
+	}
+	if b[0] != 0 || b[1] != 0 {
+		t.Errorf("bytes after overflow = %v, want zeros", b)
+	}
+}
+
+func TestRedisEventBusSequenceNumbers(t *testing.T) {
+	const publishes = 200
+
+	// Port 1 is never listening, and the canceled context fails each publish
+	// right after the event is stamped
+	bus, err := NewRedisEventBus("127.0.0.1", 1, "", 0)
+	if err != nil {
+		t.Fatalf("NewRedisEventBus: %v", err)
+	}
+	defer bus.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sequences := make([]uint64, publishes)
+	var wg sync.WaitGroup
+	for i := 0; i < publishes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			event := NewEvent("test.sequence", "id_test", nil)
+			bus.PublishContext(ctx, event)
+			sequences[i] = event.SequenceNumber
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+	for i, sequence := range sequences {
+		if want := uint64(i + 1); sequence != want {
+			t.Fatalf("sequence numbers %v have a gap or duplicate at %d, want %d", sequences, sequence, want)
+		}
+	}
+}
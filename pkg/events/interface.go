@@ -1,20 +1,43 @@
 package events
 
+import (
+"context"
+"sync"
+)
+
 // Handler represents an event handler interface
 type Handler interface {
 Handle(event *Event) error
 EventType() string
 }
 
+// Filter reports whether an event should be delivered to a handler
+type Filter func(event *Event) bool
+
 // EventBus represents the event bus interface
 type EventBus interface {
 // Publish publishes an event to the bus
 Publish(event *Event) error
 
+// PublishContext publishes an event to the bus, giving up when ctx is done
+PublishContext(ctx context.Context, event *Event) error
+
 // Subscribe subscribes a handler to events of a specific type
 Subscribe(eventType string, handler Handler) error
 
+// SubscribeWithHandle subscribes a handler to events of a specific type and
+// returns a Subscription that removes exactly this registration
+SubscribeWithHandle(eventType string, handler Handler) (*Subscription, error)
+
+// SubscribeFiltered is like SubscribeWithHandle but only delivers events for
+// which filter returns true. A nil filter delivers every event.
+SubscribeFiltered(eventType string, handler Handler, filter Filter) (*Subscription, error)
+
 // Unsubscribe removes a handler from events of a specific type
+//
+// Deprecated: handlers are matched with ==, which fails for copied handlers
+// and panics for uncomparable ones. Use SubscribeWithHandle and
+// Subscription.Unsubscribe instead.
 Unsubscribe(eventType string, handler Handler) error
 
 // Start starts the event bus
@@ -23,3 +46,41 @@ Start() error
 // Stop stops the event bus
 Stop() error
 }
+
+// Subscription is a handle to a single handler registration
+type Subscription struct {
+eventType string
+once      sync.Once
+cancel    func()
+}
+
+// NewSubscription creates a subscription handle that calls cancel once when unsubscribed
+func NewSubscription(eventType string, cancel func()) *Subscription {
+return &Subscription{eventType: eventType, cancel: cancel}
+}
+
+// EventType returns the event type the handler is subscribed to
+func (s *Subscription) EventType() string {
+return s.eventType
+}
+
+// Unsubscribe removes the handler registration. It is safe to call more than once.
+func (s *Subscription) Unsubscribe() {
+s.once.Do(func() {
+if s.cancel != nil {
+s.cancel()
+}
+})
+}
+
+// ContextStarter is implemented by event buses whose startup can be bounded by a context
+type ContextStarter interface {
+// StartContext starts the event bus, giving up when ctx is done
+StartContext(ctx context.Context) error
+}
+
+// HealthChecker is implemented by event buses that can report their health
+type HealthChecker interface {
+// Health returns an error describing why the bus is unhealthy, or nil
+Health() error
+}
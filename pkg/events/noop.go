@@ -0,0 +1,56 @@
+package events
+
+import "context"
+
+// NoopEventBus implements EventBus as a no-op for callers that don't need events
+type NoopEventBus struct{}
+
+// NewNoopEventBus creates a new no-op event bus
+func NewNoopEventBus() *NoopEventBus {
+	return &NoopEventBus{}
+}
+
+// Publish discards the event
+func (n *NoopEventBus) Publish(event *Event) error {
+	return nil
+}
+
+// PublishContext discards the event
+func (n *NoopEventBus) PublishContext(ctx context.Context, event *Event) error {
+	return nil
+}
+
+// Subscribe ignores the handler
+func (n *NoopEventBus) Subscribe(eventType string, handler Handler) error {
+	return nil
+}
+
+// SubscribeWithHandle ignores the handler and returns a handle that does nothing
+func (n *NoopEventBus) SubscribeWithHandle(eventType string, handler Handler) (*Subscription, error) {
+	return NewSubscription(eventType, nil), nil
+}
+
+// SubscribeFiltered ignores the handler and returns a handle that does nothing
+func (n *NoopEventBus) SubscribeFiltered(eventType string, handler Handler, filter Filter) (*Subscription, error) {
+	return NewSubscription(eventType, nil), nil
+}
+
+// Unsubscribe ignores the handler
+func (n *NoopEventBus) Unsubscribe(eventType string, handler Handler) error {
+	return nil
+}
+
+// Start starts the event bus
+func (n *NoopEventBus) Start() error {
+	return nil
+}
+
+// Stop stops the event bus
+func (n *NoopEventBus) Stop() error {
+	return nil
+}
+
+// Health always reports the no-op bus as healthy
+func (n *NoopEventBus) Health() error {
+	return nil
+}
@@ -3,20 +3,76 @@ package events
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// subscriptionDrainTimeout bounds how long Stop waits for the listener and
+// partition goroutines to exit
+const subscriptionDrainTimeout = 5 * time.Second
+
+// listenPingInterval is how long the listener waits for a message before
+// pinging Redis to check the connection is still up
+const listenPingInterval = 30 * time.Second
+
+// Reconnect backoff bounds for a lost pubsub connection
+const (
+	reconnectBaseDelay = 1 * time.Second
+	reconnectMaxDelay  = 30 * time.Second
+)
+
 // RedisEventBus implements EventBus using Redis pub/sub
 type RedisEventBus struct {
 	client     *redis.Client
 	ctx        context.Context
 	cancelFunc context.CancelFunc
-	handlers   map[string][]Handler
+	handlersMu sync.RWMutex
+	handlers   map[string][]registeredHandler
+	handlerIDs atomic.Uint64
+	wg         sync.WaitGroup
+	sequence   atomic.Uint64
+
+	orderedDelivery atomic.Bool
+	handlerRetries  atomic.Int64
+	retryDelay      atomic.Int64
+	partitionsMu    sync.Mutex
+	partitions      map[string]*partitionQueue
+
+	// All event types are received on one pubsub connection, so events keep
+	// the order Redis delivered them in across types. It's created with the
+	// first subscription.
+	pubsubMu sync.Mutex
+	pubsub   *redis.PubSub
+
+	// listenErr is the error that disconnected the pubsub connection, cleared
+	// once it's receiving again
+	listenMu  sync.Mutex
+	listenErr error
+}
+
+// registeredHandler is a handler along with the ID of its registration
+type registeredHandler struct {
+	id      uint64
+	handler Handler
+	filter  Filter
 }
 
-// NewRedisEventBus creates a new Redis-based event bus
+// partitionQueue holds pending deliveries for a single partition key
+type partitionQueue struct {
+	tasks   []func()
+	running bool
+}
+
+// NewRedisEventBus creates a new Redis-based event bus. The connection is
+// checked when the bus is started, not here.
 func NewRedisEventBus(host string, port int, password string, db int) (*RedisEventBus, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%d", host, port),
@@ -24,27 +80,29 @@ func NewRedisEventBus(host string, port int, password string, db int) (*RedisEve
 		DB:       db,
 	})
 
-	// Test connection
-	ctx := context.Background()
-	_, err := client.Ping(ctx).Result()
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &RedisEventBus{
 		client:     client,
 		ctx:        ctx,
 		cancelFunc: cancel,
-		handlers:   make(map[string][]Handler),
+		handlers:   make(map[string][]registeredHandler),
+		partitions: make(map[string]*partitionQueue),
 	}, nil
 }
 
 // Publish publishes an event to the bus
 func (r *RedisEventBus) Publish(event *Event) error {
+	return r.PublishContext(r.ctx, event)
+}
+
+// PublishContext publishes an event to the bus, giving up when ctx is done
+func (r *RedisEventBus) PublishContext(ctx context.Context, event *Event) error {
 	channel := fmt.Sprintf("events:%s", event.Type)
 
+	// Stamp the event with the bus sequence number
+	event.SequenceNumber = r.sequence.Add(1)
+
 	// Marshal the event to JSON
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
@@ -52,7 +110,7 @@ func (r *RedisEventBus) Publish(event *Event) error {
 	}
 
 	// Publish to Redis
-	err = r.client.Publish(r.ctx, channel, eventJSON).Err()
+	err = r.client.Publish(ctx, channel, eventJSON).Err()
 	if err != nil {
 		return fmt.Errorf("failed to publish event to Redis: %w", err)
 	}
@@ -62,68 +120,328 @@ func (r *RedisEventBus) Publish(event *Event) error {
 
 // Subscribe subscribes a handler to events of a specific type
 func (r *RedisEventBus) Subscribe(eventType string, handler Handler) error {
+	_, err := r.SubscribeWithHandle(eventType, handler)
+	return err
+}
+
+// SubscribeWithHandle subscribes a handler to events of a specific type and
+// returns a Subscription that removes exactly this registration
+func (r *RedisEventBus) SubscribeWithHandle(eventType string, handler Handler) (*Subscription, error) {
+	return r.SubscribeFiltered(eventType, handler, nil)
+}
+
+// SubscribeFiltered subscribes a handler to events of a specific type that
+// match filter. The filter runs on each received event before the handler is
+// scheduled, so skipped events cost no goroutine or partition queue slot.
+func (r *RedisEventBus) SubscribeFiltered(eventType string, handler Handler, filter Filter) (*Subscription, error) {
+	r.pubsubMu.Lock()
+	defer r.pubsubMu.Unlock()
+
 	// Add handler to local registry
-	r.handlers[eventType] = append(r.handlers[eventType], handler)
+	id := r.handlerIDs.Add(1)
+	r.handlersMu.Lock()
+	r.handlers[eventType] = append(r.handlers[eventType], registeredHandler{id: id, handler: handler, filter: filter})
+	first := len(r.handlers[eventType]) == 1
+	r.handlersMu.Unlock()
 
-	// Subscribe to Redis channel
-	channel := fmt.Sprintf("events:%s", eventType)
+	// Subscribe to the type's Redis channel on the shared connection
+	if first {
+		r.subscribeChannel(fmt.Sprintf("events:%s", eventType))
+	}
 
-	go func() {
-		pubsub := r.client.Subscribe(r.ctx, channel)
-		defer pubsub.Close()
+	return NewSubscription(eventType, func() {
+		r.removeHandler(eventType, id)
+	}), nil
+}
 
-		// Wait for confirmation that subscription is created
-		_, err := pubsub.Receive(r.ctx)
-		if err != nil {
-			return
+// removeHandler removes the handler registration with the given ID, and
+// unsubscribes from the type's Redis channel when it was the last one
+func (r *RedisEventBus) removeHandler(eventType string, id uint64) {
+	r.pubsubMu.Lock()
+	defer r.pubsubMu.Unlock()
+
+	r.handlersMu.Lock()
+	removed := false
+	handlers := r.handlers[eventType]
+	for i, registered := range handlers {
+		if registered.id == id {
+			handlers = append(handlers[:i:i], handlers[i+1:]...)
+			r.handlers[eventType] = handlers
+			removed = true
+			break
 		}
+	}
+	last := removed && len(handlers) == 0
+	if last {
+		delete(r.handlers, eventType)
+	}
+	r.handlersMu.Unlock()
 
-		// Listen for messages
-		ch := pubsub.Channel()
-		for {
-			select {
-			case msg := <-ch:
-				if msg == nil {
-					continue
-				}
+	if last {
+		r.unsubscribeChannel(fmt.Sprintf("events:%s", eventType))
+	}
+}
+
+// subscribeChannel adds a channel to the shared pubsub connection, creating
+// it and starting the listener on first use. Callers hold pubsubMu.
+func (r *RedisEventBus) subscribeChannel(channel string) {
+	if r.pubsub == nil {
+		r.pubsub = r.client.Subscribe(r.ctx, channel)
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.listen(r.pubsub)
+		}()
+		return
+	}
+
+	// The channel is remembered and subscribed again on reconnect even if
+	// this fails
+	if err := r.pubsub.Subscribe(r.ctx, channel); err != nil {
+		log.Printf("events: failed to subscribe to %s: %v", channel, err)
+	}
+}
+
+// unsubscribeChannel removes a channel from the shared pubsub connection.
+// Callers hold pubsubMu.
+func (r *RedisEventBus) unsubscribeChannel(channel string) {
+	if r.pubsub == nil {
+		return
+	}
+
+	// The channel is forgotten, and not subscribed again on reconnect, even
+	// if this fails
+	if err := r.pubsub.Unsubscribe(r.ctx, channel); err != nil && r.ctx.Err() == nil {
+		log.Printf("events: failed to unsubscribe from %s: %v", channel, err)
+	}
+}
+
+// listen dispatches events from the shared pubsub connection, in the order
+// Redis delivers them, until the bus is stopped. A lost connection is
+// recorded for Health and reconnected with backoff; the pubsub subscribes to
+// its channels again when it reconnects.
+func (r *RedisEventBus) listen(pubsub *redis.PubSub) {
+	delay := reconnectBaseDelay
+	for {
+		msg, err := pubsub.ReceiveTimeout(r.ctx, listenPingInterval)
+		if err != nil {
+			if r.ctx.Err() != nil {
+				return
+			}
 
-				// Parse the event
-				var event Event
-				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+			// A quiet connection is pinged so a dead one is noticed
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				if err = pubsub.Ping(r.ctx); err == nil {
 					continue
 				}
+			}
 
-				// Handle the event with all registered handlers
-				for _, handler := range r.handlers[eventType] {
-					go func(h Handler, e Event) {
-						h.Handle(&e)
-					}(handler, event)
-				}
+			log.Printf("events: pubsub connection lost: %v (reconnecting in %s)", err, delay)
+			r.setListenError(err)
 
+			select {
+			case <-time.After(delay):
 			case <-r.ctx.Done():
 				return
 			}
+			delay *= 2
+			if delay > reconnectMaxDelay {
+				delay = reconnectMaxDelay
+			}
+			continue
 		}
-	}()
 
-	return nil
+		// Any reply, including the subscription confirmations sent after a
+		// reconnect, means the connection is back
+		r.setListenError(nil)
+		delay = reconnectBaseDelay
+
+		message, ok := msg.(*redis.Message)
+		if !ok {
+			continue
+		}
+
+		// Parse the event
+		var event Event
+		if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+			continue
+		}
+
+		r.dispatch(strings.TrimPrefix(message.Channel, "events:"), event)
+	}
+}
+
+// setListenError records the error that disconnected the pubsub connection,
+// or clears it when err is nil
+func (r *RedisEventBus) setListenError(err error) {
+	r.listenMu.Lock()
+	defer r.listenMu.Unlock()
+	r.listenErr = err
+}
+
+// SetOrderedDelivery enables or disables ordered delivery. When enabled, events
+// sharing a PartitionKey are handled one at a time in the order they were
+// received, across event types, so e.g. a call's started event is handled
+// before its completed event. Different keys are still handled in parallel
+// and events without a PartitionKey are always dispatched concurrently.
+func (r *RedisEventBus) SetOrderedDelivery(enabled bool) {
+	r.orderedDelivery.Store(enabled)
+}
+
+// SetHandlerRetry makes the bus call a handler again, up to retries times and
+// waiting delay in between, when it returns an error. Each delivery carries
+// attempt metadata, so handlers can tell a retry with Event.IsRetry. Zero
+// retries, the default, calls each handler once. With ordered delivery, later
+// events for the same partition wait while a handler is being retried.
+func (r *RedisEventBus) SetHandlerRetry(retries int, delay time.Duration) {
+	r.handlerRetries.Store(int64(retries))
+	r.retryDelay.Store(int64(delay))
+}
+
+// dispatch hands an event to all handlers registered for its type whose filter matches it
+func (r *RedisEventBus) dispatch(eventType string, event Event) {
+	r.handlersMu.RLock()
+	registrations := append([]registeredHandler(nil), r.handlers[eventType]...)
+	r.handlersMu.RUnlock()
+
+	// Filters run outside the lock since they're caller code
+	handlers := make([]Handler, 0, len(registrations))
+	for _, registered := range registrations {
+		if registered.filter != nil {
+			e := event
+			if !registered.filter(&e) {
+				continue
+			}
+		}
+		handlers = append(handlers, registered.handler)
+	}
+	if len(handlers) == 0 {
+		return
+	}
+
+	if !r.orderedDelivery.Load() || event.PartitionKey == "" {
+		for _, handler := range handlers {
+			go r.deliver(handler, event)
+		}
+		return
+	}
+
+	r.enqueuePartition(event.PartitionKey, func() {
+		for _, handler := range handlers {
+			r.deliver(handler, event)
+		}
+	})
+}
+
+// deliver calls a handler with its own copy of the event, retrying failures
+// as set by SetHandlerRetry
+func (r *RedisEventBus) deliver(handler Handler, event Event) {
+	retries := int(r.handlerRetries.Load())
+	delay := time.Duration(r.retryDelay.Load())
+	firstSeenAt := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		// Handlers get their own metadata map, as they run concurrently
+		e := event
+		e.Metadata = make(map[string]interface{}, len(event.Metadata)+3)
+		for key, value := range event.Metadata {
+			e.Metadata[key] = value
+		}
+		if retries > 0 {
+			e.SetAttempt(attempt, retries+1, firstSeenAt)
+		}
+
+		err := handler.Handle(&e)
+		if err == nil {
+			return
+		}
+		if attempt > retries {
+			log.Printf("events: handler for %s event %s failed after %d attempts: %v", event.Type, event.ID, attempt, err)
+			return
+		}
+		log.Printf("events: handler for %s event %s failed (attempt %d of %d, retrying in %s): %v", event.Type, event.ID, attempt, retries+1, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// enqueuePartition queues a delivery for a partition key, starting a drain
+// goroutine for the key if one isn't already running
+func (r *RedisEventBus) enqueuePartition(key string, task func()) {
+	r.partitionsMu.Lock()
+	defer r.partitionsMu.Unlock()
+
+	queue, ok := r.partitions[key]
+	if !ok {
+		queue = &partitionQueue{}
+		r.partitions[key] = queue
+	}
+	queue.tasks = append(queue.tasks, task)
+
+	if !queue.running {
+		queue.running = true
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.drainPartition(key, queue)
+		}()
+	}
+}
+
+// drainPartition runs queued deliveries for a key in order and exits once the
+// queue is empty
+func (r *RedisEventBus) drainPartition(key string, queue *partitionQueue) {
+	for {
+		r.partitionsMu.Lock()
+		if len(queue.tasks) == 0 {
+			queue.running = false
+			delete(r.partitions, key)
+			r.partitionsMu.Unlock()
+			return
+		}
+		task := queue.tasks[0]
+		queue.tasks = queue.tasks[1:]
+		r.partitionsMu.Unlock()
+
+		task()
+	}
 }
 
 // Unsubscribe removes a handler from events of a specific type
+//
+// Deprecated: use SubscribeWithHandle and Subscription.Unsubscribe instead.
 func (r *RedisEventBus) Unsubscribe(eventType string, handler Handler) error {
-	handlers := r.handlers[eventType]
-	for i, h := range handlers {
-		if h == handler {
-			r.handlers[eventType] = append(handlers[:i], handlers[i+1:]...)
+	r.handlersMu.RLock()
+	var id uint64
+	for _, registered := range r.handlers[eventType] {
+		if registered.handler == handler {
+			id = registered.id
 			break
 		}
 	}
+	r.handlersMu.RUnlock()
+
+	if id != 0 {
+		r.removeHandler(eventType, id)
+	}
 	return nil
 }
 
 // Start starts the event bus
 func (r *RedisEventBus) Start() error {
-	// Redis event bus is started when created
+	return r.StartContext(r.ctx)
+}
+
+// StartContext checks the Redis connection, giving up when ctx is done
+func (r *RedisEventBus) StartContext(ctx context.Context) error {
+	if _, err := r.client.Ping(ctx).Result(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
 	return nil
 }
 
@@ -133,6 +451,24 @@ func (r *RedisEventBus) Stop() error {
 		r.cancelFunc()
 	}
 
+	r.pubsubMu.Lock()
+	if r.pubsub != nil {
+		r.pubsub.Close()
+	}
+	r.pubsubMu.Unlock()
+
+	// Wait for the listener and partition goroutines to finish
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(subscriptionDrainTimeout):
+	}
+
 	if r.client != nil {
 		return r.client.Close()
 	}
@@ -140,10 +476,20 @@ func (r *RedisEventBus) Stop() error {
 	return nil
 }
 
-// Health checks if the Redis connection is healthy
+// Health checks if the Redis connection and the pubsub connection are healthy
 func (r *RedisEventBus) Health() error {
-	_, err := r.client.Ping(r.ctx).Result()
-	return err
+	if _, err := r.client.Ping(r.ctx).Result(); err != nil {
+		return err
+	}
+
+	r.listenMu.Lock()
+	defer r.listenMu.Unlock()
+
+	if r.listenErr != nil {
+		return fmt.Errorf("event subscriptions are disconnected: %w", r.listenErr)
+	}
+
+	return nil
 }
 
 // CallProcessedEventData represents data for call-processed events
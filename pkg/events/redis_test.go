@@ -0,0 +1,210 @@
+package events
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyHandler fails its first failures calls and records every attempt
+type flakyHandler struct {
+	failures int
+	attempts []int
+	retries  []bool
+}
+
+func (h *flakyHandler) Handle(event *Event) error {
+	h.attempts = append(h.attempts, event.Attempt())
+	h.retries = append(h.retries, event.IsRetry())
+	if len(h.attempts) <= h.failures {
+		return errors.New("temporary failure")
+	}
+	return nil
+}
+
+func (h *flakyHandler) EventType() string {
+	return "test.retry"
+}
+
+func TestDeliverRetriesWithAttemptMetadata(t *testing.T) {
+	bus, err := NewRedisEventBus("127.0.0.1", 1, "", 0)
+	if err != nil {
+		t.Fatalf("NewRedisEventBus: %v", err)
+	}
+	defer bus.Stop()
+	bus.SetHandlerRetry(3, time.Millisecond)
+
+	handler := &flakyHandler{failures: 2}
+	event := NewEvent("test.retry", "redis_test", nil)
+	bus.deliver(handler, *event)
+
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(handler.attempts, want) {
+		t.Errorf("attempts = %v, want %v", handler.attempts, want)
+	}
+	if want := []bool{false, true, true}; !reflect.DeepEqual(handler.retries, want) {
+		t.Errorf("IsRetry = %v, want %v", handler.retries, want)
+	}
+	if _, ok := event.GetMetadata(MetadataAttempt); ok {
+		t.Error("attempt metadata leaked into the published event")
+	}
+}
+
+func TestDeliverWithoutRetries(t *testing.T) {
+	bus, err := NewRedisEventBus("127.0.0.1", 1, "", 0)
+	if err != nil {
+		t.Fatalf("NewRedisEventBus: %v", err)
+	}
+	defer bus.Stop()
+
+	handler := &flakyHandler{failures: 1}
+	bus.deliver(handler, *NewEvent("test.retry", "redis_test", nil))
+
+	if want := []int{1}; !reflect.DeepEqual(handler.attempts, want) {
+		t.Errorf("attempts = %v, want %v", handler.attempts, want)
+	}
+}
+
+// recordingHandler records the events it handles and sleeps in each
+type recordingHandler struct {
+	mu      sync.Mutex
+	handled []string
+	delay   time.Duration
+}
+
+func (h *recordingHandler) Handle(event *Event) error {
+	time.Sleep(h.delay)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handled = append(h.handled, event.Type)
+	return nil
+}
+
+func (h *recordingHandler) EventType() string {
+	return ""
+}
+
+func (h *recordingHandler) events() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.handled...)
+}
+
+func TestOrderedDeliveryAcrossEventTypes(t *testing.T) {
+	server := newFakeRedis(t)
+	bus := server.newBus(t)
+	bus.SetOrderedDelivery(true)
+
+	// The slow started handler must finish before completed is handled
+	handler := &recordingHandler{delay: 100 * time.Millisecond}
+	for _, eventType := range []string{EventCallStarted, EventCallCompleted} {
+		if err := bus.Subscribe(eventType, handler); err != nil {
+			t.Fatalf("Subscribe(%s): %v", eventType, err)
+		}
+	}
+	waitFor(t, time.Second, "subscriptions", func() bool {
+		return server.subscribers("events:"+EventCallStarted) == 1 && server.subscribers("events:"+EventCallCompleted) == 1
+	})
+
+	for _, eventType := range []string{EventCallStarted, EventCallCompleted} {
+		event := NewEvent(eventType, "redis_test", nil)
+		event.PartitionKey = "call-1"
+		if err := bus.Publish(event); err != nil {
+			t.Fatalf("Publish(%s): %v", eventType, err)
+		}
+	}
+
+	waitFor(t, 2*time.Second, "both events", func() bool { return len(handler.events()) == 2 })
+	if got, want := handler.events(), []string{EventCallStarted, EventCallCompleted}; !reflect.DeepEqual(got, want) {
+		t.Errorf("handled %v, want %v", got, want)
+	}
+	if got := server.connections(); got != 2 {
+		t.Errorf("bus holds %d connections, want 2 (one command, one pubsub)", got)
+	}
+}
+
+func TestStopWaitsForPartitionDrain(t *testing.T) {
+	bus, err := NewRedisEventBus("127.0.0.1", 1, "", 0)
+	if err != nil {
+		t.Fatalf("NewRedisEventBus: %v", err)
+	}
+
+	finished := make(chan struct{})
+	bus.enqueuePartition("call-1", func() {
+		time.Sleep(50 * time.Millisecond)
+		close(finished)
+	})
+	bus.Stop()
+
+	select {
+	case <-finished:
+	default:
+		t.Error("Stop returned before the partition queue drained")
+	}
+}
+
+func TestHealthReportsDroppedPubSubConnection(t *testing.T) {
+	server := newFakeRedis(t)
+	bus := server.newBus(t)
+
+	handler := &recordingHandler{}
+	if err := bus.Subscribe(EventCallCompleted, handler); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	const channel = "events:" + EventCallCompleted
+	waitFor(t, time.Second, "subscription", func() bool { return server.subscribers(channel) == 1 })
+	if err := bus.Health(); err != nil {
+		t.Fatalf("Health before disconnect: %v", err)
+	}
+
+	// Redis restarting drops every connection; the command pool redials on
+	// its own, so only the pubsub can report the drop
+	server.dropClients()
+	waitFor(t, time.Second, "Health to report the drop", func() bool { return bus.Health() != nil })
+
+	// The pubsub reconnects after its backoff and subscribes again
+	waitFor(t, 3*time.Second, "resubscription", func() bool { return server.subscribers(channel) == 1 })
+	waitFor(t, time.Second, "Health to recover", func() bool { return bus.Health() == nil })
+
+	if err := bus.Publish(NewEvent(EventCallCompleted, "redis_test", nil)); err != nil {
+		t.Fatalf("Publish after reconnect: %v", err)
+	}
+	waitFor(t, time.Second, "event after reconnect", func() bool { return len(handler.events()) == 1 })
+}
+
+func TestUnsubscribeReleasesRedisChannel(t *testing.T) {
+	server := newFakeRedis(t)
+	bus := server.newBus(t)
+	const channel = "events:" + EventCallCompleted
+
+	for cycle := 0; cycle < 3; cycle++ {
+		first, err := bus.SubscribeWithHandle(EventCallCompleted, &recordingHandler{})
+		if err != nil {
+			t.Fatalf("SubscribeWithHandle: %v", err)
+		}
+		second, err := bus.SubscribeWithHandle(EventCallCompleted, &recordingHandler{})
+		if err != nil {
+			t.Fatalf("SubscribeWithHandle: %v", err)
+		}
+		waitFor(t, time.Second, "subscription", func() bool { return server.subscribers(channel) == 1 })
+
+		// The channel stays subscribed while a handler remains
+		first.Unsubscribe()
+		time.Sleep(50 * time.Millisecond)
+		if got := server.subscribers(channel); got != 1 {
+			t.Fatalf("cycle %d: %d subscribers with one handler left, want 1", cycle, got)
+		}
+
+		second.Unsubscribe()
+		waitFor(t, time.Second, "unsubscription", func() bool { return server.subscribers(channel) == 0 })
+	}
+
+	// Nothing was published, so the shared pubsub is the only connection
+	if got := server.connections(); got != 1 {
+		t.Errorf("bus holds %d connections after subscribe cycles, want 1", got)
+	}
+	if err := bus.Health(); err != nil {
+		t.Errorf("Health after unsubscribing: %v", err)
+	}
+}
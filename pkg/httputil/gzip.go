@@ -0,0 +1,81 @@
+package httputil
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// GzipTransport requests gzip-encoded responses and decompresses them. Unlike
+// the standard transport's built-in handling, it works regardless of whether
+// the wrapped transport has compression disabled. Requests that already set
+// Accept-Encoding or Range are passed through untouched.
+type GzipTransport struct {
+	// Base is the wrapped transport, http.DefaultTransport if nil
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *GzipTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if req.Header.Get("Accept-Encoding") != "" || req.Header.Get("Range") != "" {
+		return base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		resp.Body = &gzipBody{body: resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+	return resp, nil
+}
+
+// CloseIdleConnections closes idle connections of the wrapped transport
+func (t *GzipTransport) CloseIdleConnections() {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if closer, ok := base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// gzipBody decompresses a response body, reading the gzip header on first
+// Read so errors surface where the body is consumed
+type gzipBody struct {
+	body   io.ReadCloser
+	reader *gzip.Reader
+	err    error
+}
+
+// Read implements io.Reader
+func (g *gzipBody) Read(p []byte) (int, error) {
+	if g.reader == nil && g.err == nil {
+		g.reader, g.err = gzip.NewReader(g.body)
+	}
+	if g.err != nil {
+		return 0, g.err
+	}
+	return g.reader.Read(p)
+}
+
+// Close closes the underlying response body
+func (g *gzipBody) Close() error {
+	return g.body.Close()
+}
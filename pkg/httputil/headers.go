@@ -0,0 +1,50 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+)
+
+// HeaderTransport adds headers derived from each request's context, e.g. to
+// propagate a trace ID. Headers the request already sets are left as they are.
+type HeaderTransport struct {
+	// Base is the wrapped transport, http.DefaultTransport if nil
+	Base http.RoundTripper
+	// Headers returns the headers to add for a request context
+	Headers func(ctx context.Context) map[string]string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *HeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if t.Headers == nil {
+		return base.RoundTrip(req)
+	}
+	headers := t.Headers(req.Context())
+	if len(headers) == 0 {
+		return base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	for key, value := range headers {
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, value)
+		}
+	}
+	return base.RoundTrip(req)
+}
+
+// CloseIdleConnections closes idle connections of the wrapped transport
+func (t *HeaderTransport) CloseIdleConnections() {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if closer, ok := base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
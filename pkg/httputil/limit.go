@@ -0,0 +1,53 @@
+package httputil
+
+import (
+	"fmt"
+	"io"
+)
+
+// ResponseTooLargeError is returned when a response body exceeds the configured size limit
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+// Error implements the error interface
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds the %d byte limit", e.Limit)
+}
+
+// LimitBody returns a reader over body that fails with a ResponseTooLargeError
+// once more than limit bytes have been read. A limit of zero or less disables the check.
+func LimitBody(body io.Reader, limit int64) io.Reader {
+	if limit <= 0 {
+		return body
+	}
+	return &limitedBody{body: body, limit: limit}
+}
+
+// limitedBody counts the bytes read from a response body
+type limitedBody struct {
+	body  io.Reader
+	limit int64
+	read  int64
+}
+
+// Read implements io.Reader. Bytes past the limit are never returned, so
+// readers that consume data before checking the error, like json.Decoder,
+// can't decode beyond it.
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.read > l.limit {
+		return 0, &ResponseTooLargeError{Limit: l.limit}
+	}
+
+	// Read one byte past the limit to tell a body of exactly limit bytes
+	// from a longer one
+	if remaining := l.limit - l.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.body.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		return n - int(l.read-l.limit), &ResponseTooLargeError{Limit: l.limit}
+	}
+	return n, err
+}
@@ -0,0 +1,83 @@
+package httputil
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// resetEpochThreshold separates reset headers holding a Unix timestamp from
+// ones holding the number of seconds until the window resets
+const resetEpochThreshold = 1_000_000_000
+
+// RateLimitInfo is the server-side rate limit state reported by a response
+type RateLimitInfo struct {
+	Limit     int
+	Remaining int
+	// Reset is when the current window resets, zero if not reported
+	Reset time.Time
+	// ObservedAt is when the response carrying these headers was received
+	ObservedAt time.Time
+}
+
+// ParseRateLimit reads the X-RateLimit-Limit, X-RateLimit-Remaining and
+// X-RateLimit-Reset headers, falling back to the unprefixed RateLimit-*
+// names. The reset value may be seconds until reset, a Unix timestamp or an
+// HTTP date. It returns false when the response has no remaining-quota header.
+func ParseRateLimit(header http.Header, now time.Time) (*RateLimitInfo, bool) {
+	remaining, ok := headerInt(header, "Remaining")
+	if !ok {
+		return nil, false
+	}
+
+	info := &RateLimitInfo{
+		Remaining:  remaining,
+		ObservedAt: now,
+	}
+	if limit, ok := headerInt(header, "Limit"); ok {
+		info.Limit = limit
+	}
+	if reset := rateLimitHeader(header, "Reset"); reset != "" {
+		info.Reset = parseReset(reset, now)
+	}
+
+	return info, true
+}
+
+// rateLimitHeader returns the first value of the named rate limit header
+func rateLimitHeader(header http.Header, name string) string {
+	if value := header.Get("X-RateLimit-" + name); value != "" {
+		return value
+	}
+	return header.Get("RateLimit-" + name)
+}
+
+// headerInt parses a rate limit header as an integer. Some servers send a
+// list of values for several windows, in which case the first one is used.
+func headerInt(header http.Header, name string) (int, bool) {
+	value := rateLimitHeader(header, name)
+	if i := strings.IndexAny(value, ",;"); i >= 0 {
+		value = value[:i]
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// parseReset converts a reset header value to an absolute time, zero if unparseable
+func parseReset(value string, now time.Time) time.Time {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds >= resetEpochThreshold {
+			return time.Unix(int64(seconds), 0)
+		}
+		return now.Add(time.Duration(seconds * float64(time.Second)))
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
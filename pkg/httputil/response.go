@@ -0,0 +1,64 @@
+package httputil
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// maxBodySnippet is the maximum number of body bytes kept in an UnexpectedResponseError
+const maxBodySnippet = 512
+
+// UnexpectedResponseError is returned when an API response isn't JSON, such as
+// an HTML error page from a proxy or CDN in front of VAPI
+type UnexpectedResponseError struct {
+	StatusCode  int
+	ContentType string
+	Body        string
+}
+
+// Error implements the error interface
+func (e *UnexpectedResponseError) Error() string {
+	return fmt.Sprintf("unexpected non-JSON response (status %d, content type %q): %s", e.StatusCode, e.ContentType, e.Body)
+}
+
+// NewUnexpectedResponseError creates an UnexpectedResponseError with a truncated body
+func NewUnexpectedResponseError(statusCode int, contentType string, body []byte) *UnexpectedResponseError {
+	snippet := string(body)
+	if len(snippet) > maxBodySnippet {
+		snippet = snippet[:maxBodySnippet] + "..."
+	}
+
+	return &UnexpectedResponseError{
+		StatusCode:  statusCode,
+		ContentType: contentType,
+		Body:        snippet,
+	}
+}
+
+// IsJSONContentType reports whether a Content-Type header denotes JSON. A
+// missing Content-Type is treated as JSON.
+func IsJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// CheckJSONResponse returns an UnexpectedResponseError if resp doesn't have a
+// JSON Content-Type. body is the already-read response body.
+func CheckJSONResponse(resp *http.Response, body []byte) error {
+	contentType := resp.Header.Get("Content-Type")
+	if IsJSONContentType(contentType) {
+		return nil
+	}
+
+	return NewUnexpectedResponseError(resp.StatusCode, contentType, body)
+}
@@ -0,0 +1,83 @@
+package voice
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// SetAllowedCIDRs restricts webhook requests to source addresses within one
+// of cidrs; other sources get a 403. An empty list accepts every source.
+//
+// When trustForwardedFor is set, the source is taken from the last entry of
+// the X-Forwarded-For header instead of the connection's remote address. Only
+// enable it when the server sits behind a proxy that appends that header,
+// otherwise any client can claim an allowed address.
+func (w *WebhookServer) SetAllowedCIDRs(cidrs []string, trustForwardedFor bool) error {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		// Accept bare addresses as single-host networks
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid allowed CIDR %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+
+	w.allowedNetworks = networks
+	w.trustForwardedFor = trustForwardedFor
+	return nil
+}
+
+// restrictSource wraps a webhook handler with the source address allowlist
+func (w *WebhookServer) restrictSource(next http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		if len(w.allowedNetworks) > 0 && !w.sourceAllowed(req) {
+			log.Printf("voice: rejected webhook from %s", req.RemoteAddr)
+			http.Error(rw, "Forbidden", http.StatusForbidden)
+			return
+		}
+		next(rw, req)
+	}
+}
+
+// sourceAllowed returns whether the request's source address is within an allowed network
+func (w *WebhookServer) sourceAllowed(req *http.Request) bool {
+	source := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(source); err == nil {
+		source = host
+	}
+
+	if w.trustForwardedFor {
+		if forwarded := req.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			// The proxy appends the address it saw, so the last entry is the only trusted one
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			source = strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+
+	ip := net.ParseIP(source)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range w.allowedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupAssistant saves an assistant's full config as JSON in dir and returns
+// the path of the backup file. An empty dir uses the client's StorageDir.
+// VAPI doesn't expose assistant version history, so backups are kept locally.
+func (c *Client) BackupAssistant(ctx context.Context, assistantID, dir string) (string, error) {
+	if assistantID == "" {
+		return "", fmt.Errorf("assistantID is required")
+	}
+	if dir == "" {
+		dir = c.config.StorageDir
+	}
+	if dir == "" {
+		return "", fmt.Errorf("no backup directory given and no storage directory configured")
+	}
+
+	assistantConfig, err := c.getAssistantConfig(ctx, assistantID)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(assistantConfig, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode assistant config: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	fileName := fmt.Sprintf("assistant_%s_%s.json", assistantID, time.Now().UTC().Format("20060102T150405Z"))
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write assistant backup: %w", err)
+	}
+
+	return path, nil
+}
+
+// RestoreAssistant PATCHes an assistant back to the config saved in a backup
+// file created by BackupAssistant
+func (c *Client) RestoreAssistant(ctx context.Context, assistantID, file string) (*Assistant, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read assistant backup: %w", err)
+	}
+
+	var assistantConfig map[string]interface{}
+	if err := json.Unmarshal(data, &assistantConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse assistant backup: %w", err)
+	}
+
+	// Remove read-only fields that shouldn't be included in the update
+	delete(assistantConfig, "id")
+	delete(assistantConfig, "createdAt")
+	delete(assistantConfig, "updatedAt")
+	delete(assistantConfig, "orgId")
+	delete(assistantConfig, "isServerUrlSecretSet")
+
+	return c.PatchAssistant(ctx, assistantID, assistantConfig)
+}
+
+// getAssistantConfig returns an assistant's full config as raw JSON fields
+func (c *Client) getAssistantConfig(ctx context.Context, assistantID string) (map[string]interface{}, error) {
+	endpoint := fmt.Sprintf("%s/assistant/%s", c.baseURL, assistantID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, "failed to get assistant details")
+	}
+
+	var assistantConfig map[string]interface{}
+	if err := c.decodeResponse(resp, &assistantConfig); err != nil {
+		return nil, err
+	}
+
+	return assistantConfig, nil
+}
@@ -0,0 +1,166 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/heirloomz/vapi-go-library/pkg/chat"
+)
+
+// ErrCallNotFound is returned by GetCall when VAPI has no call with the given ID
+var ErrCallNotFound = errors.New("call not found")
+
+// CreateCall places an outbound phone call, or schedules it when the request has a SchedulePlan
+func (c *Client) CreateCall(ctx context.Context, callReq *CreateCallRequest) (*Call, error) {
+	if err := c.ValidateCallRequest(callReq); err != nil {
+		return nil, err
+	}
+
+	payloadBytes, err := json.Marshal(callReq)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/call", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, responseError(resp, "failed to create call")
+	}
+
+	var call Call
+	if err := c.decodeResponse(resp, &call); err != nil {
+		return nil, err
+	}
+
+	c.record(ResourceCall, call.ID, func(ctx context.Context) error {
+		return c.DeleteCall(ctx, call.ID)
+	})
+	return &call, nil
+}
+
+// DeleteCall deletes a call and its data. Scheduled calls are cancelled; use
+// CancelScheduledCall to make sure a call that was already placed isn't deleted.
+func (c *Client) DeleteCall(ctx context.Context, callID string) error {
+	if callID == "" {
+		return fmt.Errorf("callID is required")
+	}
+
+	endpoint := fmt.Sprintf("%s/call/%s", c.baseURL, callID)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return responseError(resp, "failed to delete call")
+	}
+
+	c.forget(ResourceCall, callID)
+	return nil
+}
+
+// WithAssistantOverrides sets the assistant overrides for the call, e.g. built
+// with chat.NewAssistantOverridesBuilder
+func (r *CreateCallRequest) WithAssistantOverrides(overrides *chat.AssistantOverrides) *CreateCallRequest {
+	r.AssistantOverrides = overrides
+	return r
+}
+
+// WithVariableValues sets template variables such as {{name}} for the call,
+// keeping any other overrides and variables already set
+func (r *CreateCallRequest) WithVariableValues(values map[string]interface{}) *CreateCallRequest {
+	if r.AssistantOverrides == nil {
+		r.AssistantOverrides = &chat.AssistantOverrides{}
+	}
+	if r.AssistantOverrides.VariableValues == nil && len(values) > 0 {
+		r.AssistantOverrides.VariableValues = make(map[string]interface{}, len(values))
+	}
+	for key, value := range values {
+		r.AssistantOverrides.VariableValues[key] = value
+	}
+	return r
+}
+
+// ValidateCallRequest validates a CreateCallRequest
+func (c *Client) ValidateCallRequest(callReq *CreateCallRequest) error {
+	if callReq == nil {
+		return fmt.Errorf("request cannot be nil")
+	}
+
+	// Validate that exactly one assistant source is provided
+	var sources []string
+	if callReq.AssistantID != "" {
+		sources = append(sources, "assistantId")
+	}
+	if callReq.Assistant != nil {
+		sources = append(sources, "assistant")
+	}
+	if callReq.SquadID != "" {
+		sources = append(sources, "squadId")
+	}
+	switch len(sources) {
+	case 0:
+		return fmt.Errorf("one of assistantId, assistant, or squadId is required")
+	case 1:
+	default:
+		return fmt.Errorf("assistantId, assistant, and squadId are mutually exclusive (got %s)", strings.Join(sources, " and "))
+	}
+
+	// Overrides other than variable values belong in an inline assistant itself
+	if callReq.Assistant != nil && callReq.AssistantOverrides != nil {
+		overrides := *callReq.AssistantOverrides
+		overrides.VariableValues = nil
+		if data, err := json.Marshal(overrides); err == nil && string(data) != "{}" {
+			return fmt.Errorf("assistantOverrides other than variableValues can't be combined with an inline assistant; set them on the assistant instead")
+		}
+	}
+
+	// Validate that the outbound call has a number to call from and to
+	if callReq.PhoneNumberID == "" {
+		return fmt.Errorf("phoneNumberId is required for outbound calls")
+	}
+	if callReq.Customer == nil || (callReq.Customer.Number == "" && callReq.Customer.Phone == "") {
+		return fmt.Errorf("customer number is required for outbound calls")
+	}
+
+	return nil
+}
@@ -2,23 +2,43 @@ package voice
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	vapiconfig "github.com/heirloomz/vapi-go-library/pkg/config"
+	"github.com/heirloomz/vapi-go-library/pkg/httputil"
 )
 
+// defaultMaxResponseBytes is the default cap on a decoded response body
+const defaultMaxResponseBytes = 64 << 20
+
 // Client handles interactions with the VAPI API
 type Client struct {
 	apiToken   string
 	baseURL    string
 	httpClient *http.Client
 	config     *Config
+
+	// Raw body of the most recent decoded response, see Config.CaptureRawResponses
+	rawMu           sync.Mutex
+	lastRawResponse []byte
+
+	// Rate limit headers of the most recent response, see LastRateLimit
+	rateLimits *rateLimitTracker
+
+	// Records created resources, see SetResourceTracker
+	resources *ResourceTracker
 }
 
 // Config represents configuration for the voice client
@@ -29,6 +49,28 @@ type Config struct {
 	CacheDir   string
 	DebugDir   string
 	StorageDir string
+	UserAgent  string
+
+	// CaptureRawResponses keeps the raw body of the most recent decoded
+	// response for LastRawResponse. Off by default to avoid retaining bodies.
+	CaptureRawResponses bool
+
+	// MaxResponseBytes caps the size of a decoded response body. Zero uses
+	// the default of 64 MiB and a negative value disables the limit.
+	MaxResponseBytes int64
+
+	// Transport, when set, replaces the default HTTP transport, e.g. to
+	// fail fast on connect while allowing a long overall Timeout. The
+	// default keeps up to 20 idle connections per host for concurrent calls.
+	Transport *http.Transport
+
+	// TokenProvider, when set, supplies the API token for each request and
+	// takes precedence over APIToken
+	TokenProvider vapiconfig.TokenProvider
+
+	// HeaderProvider, when set, adds headers derived from each request's
+	// context, e.g. a trace ID
+	HeaderProvider vapiconfig.HeaderProvider
 }
 
 // NewClient creates a new VAPI client
@@ -39,45 +81,151 @@ func NewClient(config *Config) *Client {
 	if config.Timeout == 0 {
 		config.Timeout = 30 * time.Second
 	}
+	if config.UserAgent == "" {
+		config.UserAgent = vapiconfig.DefaultUserAgent
+	}
+	if config.MaxResponseBytes == 0 {
+		config.MaxResponseBytes = defaultMaxResponseBytes
+	}
 
-	// Create storage directories if they don't exist
-	if config.StorageDir != "" {
-		os.MkdirAll(config.StorageDir, os.ModePerm)
+	// Create storage directories if they don't exist; empty dirs are disabled
+	for _, dir := range []string{config.StorageDir, config.CacheDir, config.DebugDir} {
+		if dir == "" {
+			continue
+		}
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			log.Printf("voice: failed to create directory %s: %v", dir, err)
+		}
 	}
-	if config.CacheDir != "" {
-		os.MkdirAll(config.CacheDir, os.ModePerm)
+
+	transport := config.Transport
+	if transport == nil {
+		transport = vapiconfig.TransportConfig{}.NewTransport()
 	}
-	if config.DebugDir != "" {
-		os.MkdirAll(config.DebugDir, os.ModePerm)
+	var base http.RoundTripper = transport
+	if config.HeaderProvider != nil {
+		base = &httputil.HeaderTransport{Base: transport, Headers: config.HeaderProvider}
 	}
+	rateLimits := &rateLimitTracker{base: &httputil.GzipTransport{Base: base}}
 
 	return &Client{
 		apiToken:   config.APIToken,
 		baseURL:    config.BaseURL,
-		httpClient: &http.Client{Timeout: config.Timeout},
+		httpClient: &http.Client{Timeout: config.Timeout, Transport: rateLimits},
 		config:     config,
+		rateLimits: rateLimits,
 	}
 }
 
+// Close releases resources held by the client. Cache and debug files are
+// written synchronously, so only idle HTTP connections need closing.
+func (c *Client) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// token returns the API token for a request, preferring the token provider
+// the same way the chat client does
+func (c *Client) token(ctx context.Context) (string, error) {
+	vapi := vapiconfig.VAPIConfig{APIToken: c.apiToken, TokenProvider: c.config.TokenProvider}
+	return vapi.Token(ctx)
+}
+
 // getHeaders returns the headers for VAPI API requests
-func (c *Client) getHeaders() map[string]string {
+func (c *Client) getHeaders() (map[string]string, error) {
+	return c.getHeadersContext(context.Background())
+}
+
+// getHeadersContext returns the headers for VAPI API requests made with ctx
+func (c *Client) getHeadersContext(ctx context.Context) (map[string]string, error) {
+	token, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]string{
-		"Authorization": fmt.Sprintf("Bearer %s", c.apiToken),
+		"Authorization": fmt.Sprintf("Bearer %s", token),
 		"Content-Type":  "application/json",
+		"User-Agent":    c.config.UserAgent,
+	}, nil
+}
+
+// responseError builds an error for an unsuccessful response, returning an
+// httputil.UnexpectedResponseError when the body isn't JSON
+func responseError(resp *http.Response, message string) error {
+	body, _ := io.ReadAll(resp.Body)
+	if err := httputil.CheckJSONResponse(resp, body); err != nil {
+		return err
 	}
+	return fmt.Errorf("%s: %s", message, string(body))
+}
+
+// decodeResponse decodes a JSON response body into v, returning an
+// httputil.UnexpectedResponseError when the body isn't JSON and an
+// httputil.ResponseTooLargeError when it exceeds MaxResponseBytes
+func (c *Client) decodeResponse(resp *http.Response, v interface{}) error {
+	if !c.config.CaptureRawResponses {
+		if err := checkJSONContentType(resp); err != nil {
+			return err
+		}
+		return json.NewDecoder(c.limitBody(resp)).Decode(v)
+	}
+
+	body, err := c.readResponse(resp)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// LastRawResponse returns a copy of the raw body of the most recent decoded
+// response, or nil unless CaptureRawResponses is enabled. Streamed call lists
+// from EachCall aren't captured.
+func (c *Client) LastRawResponse() []byte {
+	c.rawMu.Lock()
+	defer c.rawMu.Unlock()
+
+	if c.lastRawResponse == nil {
+		return nil
+	}
+	return append([]byte(nil), c.lastRawResponse...)
+}
+
+// checkJSONContentType returns an httputil.UnexpectedResponseError when the response isn't JSON
+func checkJSONContentType(resp *http.Response) error {
+	contentType := resp.Header.Get("Content-Type")
+	if !httputil.IsJSONContentType(contentType) {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return httputil.NewUnexpectedResponseError(resp.StatusCode, contentType, body)
+	}
+	return nil
+}
+
+// limitBody wraps the response body with the client's size limit
+func (c *Client) limitBody(resp *http.Response) io.Reader {
+	return httputil.LimitBody(resp.Body, c.config.MaxResponseBytes)
 }
 
 // ListAssistants returns a list of VAPI assistants
 func (c *Client) ListAssistants() ([]Assistant, error) {
+	return c.ListAssistantsContext(context.Background())
+}
+
+// ListAssistantsContext returns a list of VAPI assistants, honoring ctx cancellation
+func (c *Client) ListAssistantsContext(ctx context.Context) ([]Assistant, error) {
 	url := fmt.Sprintf("%s/assistant", c.baseURL)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	// Add headers
-	for key, value := range c.getHeaders() {
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
 		req.Header.Add(key, value)
 	}
 
@@ -88,12 +236,57 @@ func (c *Client) ListAssistants() ([]Assistant, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("error listing assistants: %s", string(body))
+		return nil, responseError(resp, "error listing assistants")
 	}
 
 	var assistants []Assistant
-	if err := json.NewDecoder(resp.Body).Decode(&assistants); err != nil {
+	if err := c.decodeList(resp, &assistants); err != nil {
+		return nil, err
+	}
+
+	return assistants, nil
+}
+
+// ListFullAssistants returns assistants with their complete configuration
+// (model, voice, transcriber, ...) as returned by the list endpoint, avoiding
+// a GetAssistant call per assistant
+func (c *Client) ListFullAssistants(ctx context.Context, opts *AssistantListOptions) ([]FullAssistant, error) {
+	query := url.Values{}
+	if opts != nil && opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	endpoint := fmt.Sprintf("%s/assistant", c.baseURL)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, "error listing assistants")
+	}
+
+	var assistants []FullAssistant
+	if err := c.decodeList(resp, &assistants); err != nil {
 		return nil, err
 	}
 
@@ -110,7 +303,11 @@ func (c *Client) GetAssistant(assistantID string) (*Assistant, error) {
 	}
 
 	// Add headers
-	for key, value := range c.getHeaders() {
+	headers, err := c.getHeaders()
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
 		req.Header.Add(key, value)
 	}
 
@@ -121,12 +318,11 @@ func (c *Client) GetAssistant(assistantID string) (*Assistant, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("error getting assistant: %s", string(body))
+		return nil, responseError(resp, "error getting assistant")
 	}
 
 	var assistant Assistant
-	if err := json.NewDecoder(resp.Body).Decode(&assistant); err != nil {
+	if err := c.decodeOne(resp, &assistant); err != nil {
 		return nil, err
 	}
 
@@ -144,7 +340,11 @@ func (c *Client) UpdateAssistant(assistantID string, updateReq *UpdateAssistantR
 	}
 
 	// Add headers
-	for key, value := range c.getHeaders() {
+	headers, err := c.getHeaders()
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
 		req.Header.Add(key, value)
 	}
 
@@ -154,13 +354,13 @@ func (c *Client) UpdateAssistant(assistantID string, updateReq *UpdateAssistantR
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
+		err := responseError(resp, "failed to get assistant details")
 		resp.Body.Close()
-		return nil, fmt.Errorf("failed to get assistant details: %s", string(body))
+		return nil, err
 	}
 
 	var assistantConfig map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&assistantConfig); err != nil {
+	if err := c.decodeResponse(resp, &assistantConfig); err != nil {
 		resp.Body.Close()
 		return nil, err
 	}
@@ -214,7 +414,11 @@ func (c *Client) UpdateAssistant(assistantID string, updateReq *UpdateAssistantR
 	}
 
 	// Add headers
-	for key, value := range c.getHeaders() {
+	headers, err = c.getHeaders()
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
 		updateReq2.Header.Add(key, value)
 	}
 
@@ -225,25 +429,37 @@ func (c *Client) UpdateAssistant(assistantID string, updateReq *UpdateAssistantR
 	defer updateResp.Body.Close()
 
 	if updateResp.StatusCode != http.StatusOK && updateResp.StatusCode != http.StatusCreated && updateResp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(updateResp.Body)
-		return nil, fmt.Errorf("failed to update assistant: %s", string(body))
+		return nil, responseError(updateResp, "failed to update assistant")
 	}
 
 	// Return the updated assistant
 	return c.GetAssistant(assistantID)
 }
 
-// ListCalls returns a list of VAPI calls for an assistant
-func (c *Client) ListCalls(assistantID string, limit int) ([]Call, error) {
-	url := fmt.Sprintf("%s/call?assistantId=%s&limit=%d", c.baseURL, assistantID, limit)
+// PatchAssistant sends a partial update to an assistant. Only the fields in
+// patch are changed; unlike UpdateAssistant, the current config isn't fetched first.
+func (c *Client) PatchAssistant(ctx context.Context, assistantID string, patch map[string]interface{}) (*Assistant, error) {
+	if assistantID == "" {
+		return nil, fmt.Errorf("assistantID is required")
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	payloadBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/assistant/%s", c.baseURL, assistantID)
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return nil, err
 	}
 
 	// Add headers
-	for key, value := range c.getHeaders() {
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
 		req.Header.Add(key, value)
 	}
 
@@ -253,134 +469,242 @@ func (c *Client) ListCalls(assistantID string, limit int) ([]Call, error) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("error listing calls: %s", string(body))
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, responseError(resp, "failed to patch assistant")
 	}
 
-	var calls []Call
-	if err := json.NewDecoder(resp.Body).Decode(&calls); err != nil {
+	var assistant Assistant
+	if err := c.decodeResponse(resp, &assistant); err != nil {
 		return nil, err
 	}
 
-	return calls, nil
+	return &assistant, nil
 }
 
-// GetCall returns a VAPI call by ID
-func (c *Client) GetCall(callID string) (*Call, error) {
-	url := fmt.Sprintf("%s/call/%s", c.baseURL, callID)
+// CreateAssistant creates an assistant from a full assistant config
+func (c *Client) CreateAssistant(ctx context.Context, assistantConfig map[string]interface{}) (*Assistant, error) {
+	req, _, err := c.newCreateAssistantRequest(ctx, assistantConfig)
+	if err != nil {
+		return nil, err
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, responseError(resp, "failed to create assistant")
+	}
+
+	var assistant Assistant
+	if err := c.decodeResponse(resp, &assistant); err != nil {
+		return nil, err
+	}
+
+	c.record(ResourceAssistant, assistant.ID, func(ctx context.Context) error {
+		return c.DeleteAssistant(ctx, assistant.ID)
+	})
+	return &assistant, nil
+}
+
+// newCreateAssistantRequest builds the HTTP request CreateAssistant sends,
+// returning its body alongside
+func (c *Client) newCreateAssistantRequest(ctx context.Context, assistantConfig map[string]interface{}) (*http.Request, []byte, error) {
+	payloadBytes, err := json.Marshal(assistantConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/assistant", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Add headers
-	for key, value := range c.getHeaders() {
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range headers {
 		req.Header.Add(key, value)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	return req, payloadBytes, nil
+}
+
+// DeleteAssistant deletes an assistant
+func (c *Client) DeleteAssistant(ctx context.Context, assistantID string) error {
+	if assistantID == "" {
+		return fmt.Errorf("assistantID is required")
+	}
+
+	endpoint := fmt.Sprintf("%s/assistant/%s", c.baseURL, assistantID)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", endpoint, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("error getting call: %s", string(body))
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
 	}
 
-	var call Call
-	if err := json.NewDecoder(resp.Body).Decode(&call); err != nil {
-		return nil, err
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
 
-	// Save to debug directory if configured
-	if c.config.DebugDir != "" {
-		callData, _ := json.MarshalIndent(call, "", "  ")
-		os.WriteFile(fmt.Sprintf("%s/call_data_%s.json", c.config.DebugDir, callID), callData, 0644)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return responseError(resp, "failed to delete assistant")
 	}
 
-	return &call, nil
+	c.forget(ResourceAssistant, assistantID)
+	return nil
 }
 
-// UploadFile uploads a file to VAPI
-func (c *Client) UploadFile(filePath string) (*File, error) {
-	fileName := filepath.Base(filePath)
+// ListCalls returns a list of VAPI calls for an assistant
+func (c *Client) ListCalls(assistantID string, limit int) ([]Call, error) {
+	query := url.Values{}
+	query.Set("assistantId", assistantID)
+	query.Set("limit", strconv.Itoa(limit))
 
-	// Create a buffer to store the multipart form data
-	var requestBody bytes.Buffer
-	multipartWriter := multipart.NewWriter(&requestBody)
+	return c.listCalls(context.Background(), query)
+}
 
-	// Determine the MIME type
-	mimeType := c.detectMimeType(filePath, fileName)
+// listCalls returns the VAPI calls matching the given query parameters
+func (c *Client) listCalls(ctx context.Context, query url.Values) ([]Call, error) {
+	endpoint := fmt.Sprintf("%s/call?%s", c.baseURL, query.Encode())
 
-	// Add the content type field first
-	err := multipartWriter.WriteField("contentType", mimeType)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create a custom form file field with the correct Content-Type
-	h := make(map[string][]string)
-	h["Content-Disposition"] = []string{fmt.Sprintf(`form-data; name="file"; filename="%s"`, fileName)}
-	h["Content-Type"] = []string{mimeType}
-	fileField, err := multipartWriter.CreatePart(h)
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
 	if err != nil {
 		return nil, err
 	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
 
-	// Open the file
-	fileHandle, err := os.Open(filePath)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer fileHandle.Close()
+	defer resp.Body.Close()
 
-	// Copy the file content to the form field
-	_, err = io.Copy(fileField, fileHandle)
-	if err != nil {
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, "error listing calls")
+	}
+
+	var calls []Call
+	if err := c.decodeList(resp, &calls); err != nil {
 		return nil, err
 	}
 
-	// Close the multipart writer to finalize the form
-	multipartWriter.Close()
+	return calls, nil
+}
 
-	// Create the request
-	url := fmt.Sprintf("%s/file", c.baseURL)
-	req, err := http.NewRequest("POST", url, &requestBody)
+// GetCall returns a VAPI call by ID
+func (c *Client) GetCall(callID string) (*Call, error) {
+	return c.GetCallContext(context.Background(), callID)
+}
+
+// GetCallContext returns a VAPI call by ID, honoring ctx cancellation
+func (c *Client) GetCallContext(ctx context.Context, callID string) (*Call, error) {
+	url := fmt.Sprintf("%s/call/%s", c.baseURL, callID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Set the content type with the boundary
-	req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiToken))
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
 
-	// Send the request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to upload file: %s", string(body))
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %v", ErrCallNotFound, responseError(resp, "error getting call"))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp, "error getting call")
 	}
 
-	// Parse the response
-	var uploadedFile File
-	if err := json.NewDecoder(resp.Body).Decode(&uploadedFile); err != nil {
+	var call Call
+	if err := c.decodeOne(resp, &call); err != nil {
+		return nil, err
+	}
+
+	// Save to debug directory if configured
+	if c.config.DebugDir != "" {
+		callData, _ := json.MarshalIndent(call, "", "  ")
+		os.WriteFile(fmt.Sprintf("%s/call_data_%s.json", c.config.DebugDir, callID), callData, 0644)
+	}
+
+	return &call, nil
+}
+
+// GetCallCost returns the cost of a call broken down by cost type
+func (c *Client) GetCallCost(ctx context.Context, callID string) (*CostSummary, error) {
+	call, err := c.GetCallContext(ctx, callID)
+	if err != nil {
 		return nil, err
 	}
 
-	return &uploadedFile, nil
+	summary := &CostSummary{
+		CallID: call.ID,
+		Total:  call.Cost,
+		ByType: make(map[string]float64),
+		Items:  call.Costs,
+	}
+
+	var itemsTotal float64
+	for _, cost := range call.Costs {
+		summary.ByType[cost.Type] += cost.Cost
+		itemsTotal += cost.Cost
+	}
+
+	// Fall back to the sum of the items if the call has no total
+	if summary.Total == 0 {
+		summary.Total = itemsTotal
+	}
+
+	return summary, nil
+}
+
+// UploadFile uploads a file to VAPI
+func (c *Client) UploadFile(filePath string) (*File, error) {
+	return c.UploadFileWithProgress(context.Background(), filePath, nil)
 }
 
 // CreateQueryTool creates a query tool for the knowledge base
 func (c *Client) CreateQueryTool(fileIDs []string, toolName, description string) (*Tool, error) {
+	return c.CreateQueryToolContext(context.Background(), fileIDs, toolName, description)
+}
+
+// CreateQueryToolContext creates a query tool for the knowledge base, honoring ctx cancellation
+func (c *Client) CreateQueryToolContext(ctx context.Context, fileIDs []string, toolName, description string) (*Tool, error) {
 	payload := CreateToolRequest{
 		Type: "query",
 		Function: ToolFunction{
@@ -403,13 +727,17 @@ func (c *Client) CreateQueryTool(fileIDs []string, toolName, description string)
 
 	// Create the request
 	url := fmt.Sprintf("%s/tool", c.baseURL)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return nil, err
 	}
 
 	// Add headers
-	for key, value := range c.getHeaders() {
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
 		req.Header.Add(key, value)
 	}
 
@@ -421,31 +749,43 @@ func (c *Client) CreateQueryTool(fileIDs []string, toolName, description string)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to create query tool: %s", string(body))
+		return nil, responseError(resp, "failed to create query tool")
 	}
 
 	// Parse the response
 	var tool Tool
-	if err := json.NewDecoder(resp.Body).Decode(&tool); err != nil {
+	if err := c.decodeResponse(resp, &tool); err != nil {
 		return nil, err
 	}
 
+	c.record(ResourceTool, tool.ID, func(ctx context.Context) error {
+		return c.DeleteTool(ctx, tool.ID)
+	})
 	return &tool, nil
 }
 
 // AttachToolToAssistant attaches a tool to an assistant
 func (c *Client) AttachToolToAssistant(assistantID, toolID string) error {
+	return c.AttachToolsToAssistant(context.Background(), assistantID, []string{toolID})
+}
+
+// AttachToolsToAssistant attaches several tools to an assistant with a single
+// GET and PATCH, skipping tools that are already attached
+func (c *Client) AttachToolsToAssistant(ctx context.Context, assistantID string, toolIDs []string) error {
 	// First get the current assistant config
 	url := fmt.Sprintf("%s/assistant/%s", c.baseURL, assistantID)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
 	// Add headers
-	for key, value := range c.getHeaders() {
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
 		req.Header.Add(key, value)
 	}
 
@@ -455,50 +795,61 @@ func (c *Client) AttachToolToAssistant(assistantID, toolID string) error {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
+		err := responseError(resp, "failed to get assistant details")
 		resp.Body.Close()
-		return fmt.Errorf("failed to get assistant details: %s", string(body))
+		return err
 	}
 
 	var assistantConfig map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&assistantConfig); err != nil {
+	if err := c.decodeResponse(resp, &assistantConfig); err != nil {
 		resp.Body.Close()
 		return err
 	}
 	resp.Body.Close()
 
 	// Update the toolIds
-	if _, ok := assistantConfig["model"]; !ok {
+	if assistantConfig["model"] == nil {
 		assistantConfig["model"] = map[string]interface{}{}
 	}
 
-	model := assistantConfig["model"].(map[string]interface{})
+	model, ok := assistantConfig["model"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("assistant %s has an unexpected model: %T", assistantID, assistantConfig["model"])
+	}
 
-	// Add the tool ID to the list, creating it if it doesn't exist
-	var toolIDs []string
-	if existingToolIDs, ok := model["toolIds"]; ok {
-		for _, id := range existingToolIDs.([]interface{}) {
-			toolIDs = append(toolIDs, id.(string))
+	// Collect the existing tool IDs
+	var existingIDs []string
+	attached := make(map[string]bool)
+	if rawToolIDs, ok := model["toolIds"]; ok && rawToolIDs != nil {
+		existingToolIDs, ok := rawToolIDs.([]interface{})
+		if !ok {
+			return fmt.Errorf("assistant %s has unexpected toolIds: %T", assistantID, rawToolIDs)
+		}
+		for _, id := range existingToolIDs {
+			toolID, ok := id.(string)
+			if !ok {
+				return fmt.Errorf("assistant %s has a non-string tool ID: %v", assistantID, id)
+			}
+			existingIDs = append(existingIDs, toolID)
+			attached[toolID] = true
 		}
 	}
 
-	// Check if tool ID already exists
-	toolExists := false
-	for _, id := range toolIDs {
-		if id == toolID {
-			toolExists = true
-			break
+	// Add the tool IDs that aren't attached yet
+	updatedIDs := existingIDs
+	for _, toolID := range toolIDs {
+		if toolID == "" || attached[toolID] {
+			continue
 		}
+		attached[toolID] = true
+		updatedIDs = append(updatedIDs, toolID)
 	}
 
-	// Add the tool ID if it doesn't exist
-	if !toolExists {
-		toolIDs = append(toolIDs, toolID)
-		model["toolIds"] = toolIDs
-	} else {
-		// Tool already attached
+	if len(updatedIDs) == len(existingIDs) {
+		// All tools already attached
 		return nil
 	}
+	model["toolIds"] = updatedIDs
 
 	// Remove read-only fields that shouldn't be included in the update
 	delete(assistantConfig, "id")
@@ -514,13 +865,13 @@ func (c *Client) AttachToolToAssistant(assistantID, toolID string) error {
 		return err
 	}
 
-	updateReq, err := http.NewRequest("PATCH", updateURL, bytes.NewBuffer(updatePayloadBytes))
+	updateReq, err := http.NewRequestWithContext(ctx, "PATCH", updateURL, bytes.NewBuffer(updatePayloadBytes))
 	if err != nil {
 		return err
 	}
 
 	// Add headers
-	for key, value := range c.getHeaders() {
+	for key, value := range headers {
 		updateReq.Header.Add(key, value)
 	}
 
@@ -531,10 +882,49 @@ func (c *Client) AttachToolToAssistant(assistantID, toolID string) error {
 	defer updateResp.Body.Close()
 
 	if updateResp.StatusCode != http.StatusOK && updateResp.StatusCode != http.StatusCreated && updateResp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(updateResp.Body)
-		return fmt.Errorf("failed to update assistant: %s", string(body))
+		return responseError(updateResp, "failed to update assistant")
+	}
+
+	for _, toolID := range updatedIDs[len(existingIDs):] {
+		c.recordAttachment(assistantID, toolID)
+	}
+
+	return nil
+}
+
+// DetachToolsFromAssistant removes tools from an assistant, leaving the tools
+// themselves in place. Tools that aren't attached are ignored.
+func (c *Client) DetachToolsFromAssistant(ctx context.Context, assistantID string, toolIDs []string) error {
+	assistantConfig, err := c.getAssistantConfig(ctx, assistantID)
+	if err != nil {
+		return err
+	}
+
+	model, _ := assistantConfig["model"].(map[string]interface{})
+	existingToolIDs, _ := model["toolIds"].([]interface{})
+
+	detach := make(map[string]bool, len(toolIDs))
+	for _, toolID := range toolIDs {
+		detach[toolID] = true
 	}
 
+	remainingIDs := []string{}
+	for _, id := range existingToolIDs {
+		if toolID, ok := id.(string); ok && !detach[toolID] {
+			remainingIDs = append(remainingIDs, toolID)
+		}
+	}
+
+	if len(remainingIDs) != len(existingToolIDs) {
+		model["toolIds"] = remainingIDs
+		if _, err := c.PatchAssistant(ctx, assistantID, map[string]interface{}{"model": model}); err != nil {
+			return err
+		}
+	}
+
+	for _, toolID := range toolIDs {
+		c.forget(ResourceToolAttachment, assistantID+"/"+toolID)
+	}
 	return nil
 }
 
@@ -590,132 +980,5 @@ func (c *Client) detectMimeType(filePath string, fileName string) string {
 
 // ExtractTranscript extracts the transcript from a VAPI call
 func (c *Client) ExtractTranscript(call *Call) []Message {
-	// Check for transcript in analysis
-	if call.Analysis != nil && call.Analysis.Transcript != nil && len(call.Analysis.Transcript) > 0 {
-		return call.Analysis.Transcript
-	}
-
-	// Check for other transcript sources
-	if call.Transcript != nil {
-		// Check if transcript is a string
-		if transcriptStr, ok := call.Transcript.(string); ok && transcriptStr != "" {
-			return c.parseTranscriptContent(transcriptStr)
-		}
-
-		// Check if transcript is a slice of messages
-		if transcriptMsgs, ok := call.Transcript.([]Message); ok && len(transcriptMsgs) > 0 {
-			return transcriptMsgs
-		}
-	}
-
-	if call.Messages != nil && len(call.Messages) > 0 {
-		return call.Messages
-	}
-
-	if call.Conversation != nil && len(call.Conversation) > 0 {
-		return call.Conversation
-	}
-
-	// Check nested in artifacts
-	if call.Artifacts != nil {
-		for _, artifact := range call.Artifacts {
-			if artifact.Transcript != nil && len(artifact.Transcript) > 0 {
-				return artifact.Transcript
-			}
-
-			if artifact.Content != "" {
-				if strings.Contains(artifact.Content, "Transcript") ||
-					strings.Contains(artifact.Content, "AI") ||
-					strings.Contains(artifact.Content, "User") {
-					return c.parseTranscriptContent(artifact.Content)
-				}
-			}
-		}
-	}
-
-	return []Message{}
-}
-
-// parseTranscriptContent parses transcript content from a string
-func (c *Client) parseTranscriptContent(content string) []Message {
-	if content == "" {
-		return []Message{}
-	}
-
-	lines := strings.Split(strings.TrimSpace(content), "\n")
-	transcript := []Message{}
-
-	currentRole := ""
-	currentText := ""
-
-	// Skip first line if it's just "Transcript"
-	startIdx := 0
-	if len(lines) > 0 && strings.Contains(lines[0], "Transcript") {
-		startIdx = 1
-	}
-
-	for i := startIdx; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-
-		// Skip empty lines
-		if line == "" {
-			continue
-		}
-
-		// Check for new speaker
-		if strings.HasPrefix(line, "AI") || strings.HasPrefix(line, "BOT") || strings.HasPrefix(line, "ASSISTANT") {
-			// Save previous message if exists
-			if currentRole != "" && currentText != "" {
-				transcript = append(transcript, Message{
-					Role: currentRole,
-					Text: strings.TrimSpace(currentText),
-				})
-			}
-
-			currentRole = "assistant"
-			// Extract text after the speaker indicator
-			parts := strings.SplitN(line, " ", 2)
-			if len(parts) > 1 {
-				currentText = strings.TrimSpace(parts[1])
-			} else {
-				currentText = ""
-			}
-		} else if strings.HasPrefix(line, "User") || strings.HasPrefix(line, "USER") || strings.HasPrefix(line, "CLIENT") {
-			// Save previous message if exists
-			if currentRole != "" && currentText != "" {
-				transcript = append(transcript, Message{
-					Role: currentRole,
-					Text: strings.TrimSpace(currentText),
-				})
-			}
-
-			currentRole = "user"
-			// Extract text after the speaker indicator
-			parts := strings.SplitN(line, " ", 2)
-			if len(parts) > 1 {
-				currentText = strings.TrimSpace(parts[1])
-			} else {
-				currentText = ""
-			}
-		} else {
-			// Append to current text if we have a role
-			if currentRole != "" {
-				if currentText != "" {
-					currentText += " " + line
-				} else {
-					currentText = line
-				}
-			}
-		}
-	}
-
-	// Add the last message
-	if currentRole != "" && currentText != "" {
-		transcript = append(transcript, Message{
-			Role: currentRole,
-			Text: strings.TrimSpace(currentText),
-		})
-	}
-
-	return transcript
+	return call.NormalizedTranscript()
 }
@@ -0,0 +1,89 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAttachToolsToAssistantMalformedToolIDs(t *testing.T) {
+	tests := []struct {
+		name      string
+		assistant string
+	}{
+		{name: "toolIds not a list", assistant: `{"id":"asst_1","model":{"toolIds":"tool_1"}}`},
+		{name: "non-string tool ID", assistant: `{"id":"asst_1","model":{"toolIds":["tool_1",7]}}`},
+		{name: "model not an object", assistant: `{"id":"asst_1","model":"gpt-4o"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patched bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPatch {
+					patched = true
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, tt.assistant)
+			}))
+			defer server.Close()
+
+			client := NewClient(&Config{APIToken: "test-token", BaseURL: server.URL})
+			if err := client.AttachToolsToAssistant(context.Background(), "asst_1", []string{"tool_2"}); err == nil {
+				t.Error("AttachToolsToAssistant succeeded, want an error")
+			}
+			if patched {
+				t.Error("assistant was updated despite malformed tool IDs")
+			}
+		})
+	}
+}
+
+func TestAttachToolsToAssistantWithoutToolIDs(t *testing.T) {
+	var patched bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPatch {
+			patched = true
+		}
+		fmt.Fprint(w, `{"id":"asst_1","model":{"provider":"openai"}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{APIToken: "test-token", BaseURL: server.URL})
+	if err := client.AttachToolsToAssistant(context.Background(), "asst_1", []string{"tool_1"}); err != nil {
+		t.Fatalf("AttachToolsToAssistant: %v", err)
+	}
+	if !patched {
+		t.Error("assistant was not updated")
+	}
+}
+
+func TestDryRunCreateAssistantSendsNothing(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{APIToken: "test-token", BaseURL: server.URL})
+	dryRun, err := client.DryRunCreateAssistant(context.Background(), map[string]interface{}{"name": "Receptionist"})
+	if err != nil {
+		t.Fatalf("DryRunCreateAssistant: %v", err)
+	}
+
+	if requests != 0 {
+		t.Errorf("dry run sent %d requests, want 0", requests)
+	}
+	if dryRun.Method != http.MethodPost || dryRun.URL != server.URL+"/assistant" {
+		t.Errorf("request = %s %s, want POST %s/assistant", dryRun.Method, dryRun.URL, server.URL)
+	}
+	if got := string(dryRun.Body); got != `{"name":"Receptionist"}` {
+		t.Errorf("body = %s, want the marshaled config", got)
+	}
+	if got := dryRun.Headers.Get("Authorization"); got != "Bearer [REDACTED]" {
+		t.Errorf("Authorization = %q, want it redacted", got)
+	}
+}
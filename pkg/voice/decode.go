@@ -0,0 +1,98 @@
+package voice
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// listEnvelopeKey is the key some VAPI list endpoints wrap their results in
+const listEnvelopeKey = "results"
+
+// readResponse reads a JSON response body within MaxResponseBytes, keeping it
+// for LastRawResponse when CaptureRawResponses is enabled
+func (c *Client) readResponse(resp *http.Response) ([]byte, error) {
+	if err := checkJSONContentType(resp); err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(c.limitBody(resp))
+	if err != nil {
+		return nil, err
+	}
+	if c.config.CaptureRawResponses {
+		c.rawMu.Lock()
+		c.lastRawResponse = body
+		c.rawMu.Unlock()
+	}
+	return body, nil
+}
+
+// decodeList decodes a list response into v, a pointer to a slice. Both a
+// top-level array and a {"results": [...]} envelope are accepted, and an
+// object without results, as sometimes returned for empty lists, decodes as
+// an empty list.
+func (c *Client) decodeList(resp *http.Response, v interface{}) error {
+	body, err := c.readResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	list, err := unwrapList(body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(list, v)
+}
+
+// decodeOne decodes a single-item response into v, accepting both a bare
+// object and an array holding exactly one object
+func (c *Client) decodeOne(resp *http.Response, v interface{}) error {
+	body, err := c.readResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	item, err := unwrapOne(body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(item, v)
+}
+
+// unwrapList returns the JSON array held by a list response body
+func unwrapList(body []byte) ([]byte, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' {
+		return body, nil
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+	results, ok := envelope[listEnvelopeKey]
+	if !ok {
+		return []byte("[]"), nil
+	}
+	return results, nil
+}
+
+// unwrapOne returns the JSON object held by a single-item response body
+func unwrapOne(body []byte) ([]byte, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		return body, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, err
+	}
+	if len(items) != 1 {
+		return nil, fmt.Errorf("expected a single object, got an array of %d", len(items))
+	}
+	return items[0], nil
+}
@@ -0,0 +1,160 @@
+package voice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrNoSuccessEvaluation is returned by Succeeded when the call has no success evaluation
+var ErrNoSuccessEvaluation = errors.New("call has no success evaluation")
+
+// Thresholds used by Succeeded for numeric success evaluations
+const (
+	// numericScalePassScore is the lowest passing score on a 1-10 scale
+	numericScalePassScore = 6
+	// percentagePassScore is the lowest passing percentage
+	percentagePassScore = 50
+)
+
+// descriptiveVerdicts maps the textual results of the pass/fail, descriptive
+// scale and Likert scale rubrics to whether the call succeeded
+var descriptiveVerdicts = map[string]bool{
+	"true":              true,
+	"false":             false,
+	"pass":              true,
+	"passed":            true,
+	"fail":              false,
+	"failed":            false,
+	"yes":               true,
+	"no":                false,
+	"excellent":         true,
+	"good":              true,
+	"fair":              false,
+	"poor":              false,
+	"strongly agree":    true,
+	"agree":             true,
+	"neutral":           false,
+	"disagree":          false,
+	"strongly disagree": false,
+}
+
+// SuccessEvaluation is the result of an assistant's success evaluation plan.
+// VAPI returns it as a boolean, number or string depending on the rubric, and
+// some rubrics wrap it in an object with a rationale.
+type SuccessEvaluation struct {
+	// Value is the result as text, e.g. "true", "8" or "Good"
+	Value string
+	// Verdict is set when the result is a boolean or pass/fail
+	Verdict *bool
+	// Score is set when the result is a number or percentage
+	Score *float64
+	// Percentage reports whether Score is a percentage rather than a 1-10 score
+	Percentage bool
+	// Rationale explains the result, when the rubric provides one
+	Rationale string
+
+	raw json.RawMessage
+}
+
+// UnmarshalJSON accepts a boolean, number, string or an object holding the
+// result under "result", "score" or "verdict" and its rationale
+func (e *SuccessEvaluation) UnmarshalJSON(data []byte) error {
+	*e = SuccessEvaluation{raw: append(json.RawMessage(nil), data...)}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if object, ok := value.(map[string]interface{}); ok {
+		for _, key := range []string{"rationale", "reason", "explanation"} {
+			if rationale, ok := object[key].(string); ok {
+				e.Rationale = rationale
+				break
+			}
+		}
+		value = nil
+		for _, key := range []string{"result", "score", "verdict"} {
+			if result, ok := object[key]; ok {
+				value = result
+				break
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case bool:
+		e.Value = strconv.FormatBool(v)
+		e.Verdict = &v
+	case float64:
+		e.Value = strconv.FormatFloat(v, 'f', -1, 64)
+		e.Score = &v
+	case string:
+		e.Value = v
+		e.parseText(v)
+	case nil:
+	default:
+		return fmt.Errorf("unsupported success evaluation %s", string(data))
+	}
+	return nil
+}
+
+// parseText fills Verdict or Score from a textual result
+func (e *SuccessEvaluation) parseText(text string) {
+	text = strings.TrimSpace(text)
+	if verdict, err := strconv.ParseBool(text); err == nil {
+		e.Verdict = &verdict
+		return
+	}
+
+	number := strings.TrimSuffix(text, "%")
+	if score, err := strconv.ParseFloat(strings.TrimSpace(number), 64); err == nil {
+		e.Score = &score
+		e.Percentage = number != text
+	}
+}
+
+// MarshalJSON writes the evaluation back as VAPI sent it
+func (e SuccessEvaluation) MarshalJSON() ([]byte, error) {
+	if e.raw != nil {
+		return e.raw, nil
+	}
+	return json.Marshal(e.Value)
+}
+
+// Passed interprets the evaluation as pass or fail:
+//   - booleans and pass/fail rubrics use the verdict as-is
+//   - numeric scale (1-10) scores pass at 6 or above
+//   - percentages, and scores above 10, pass at 50 or above
+//   - descriptive scale results pass for "Excellent" and "Good"
+//   - Likert scale results pass for "Agree" and "Strongly Agree"
+//
+// Other results, such as checklists, return an error.
+func (e *SuccessEvaluation) Passed() (bool, error) {
+	if e.Verdict != nil {
+		return *e.Verdict, nil
+	}
+	if e.Score != nil {
+		if e.Percentage || *e.Score > 10 {
+			return *e.Score >= percentagePassScore, nil
+		}
+		return *e.Score >= numericScalePassScore, nil
+	}
+	if verdict, ok := descriptiveVerdicts[strings.ToLower(strings.TrimSpace(e.Value))]; ok {
+		return verdict, nil
+	}
+	return false, fmt.Errorf("cannot interpret success evaluation %q as pass or fail", e.Value)
+}
+
+// Succeeded reports whether the call passed its success evaluation, as
+// interpreted by SuccessEvaluation.Passed. It returns ErrNoSuccessEvaluation
+// when the call wasn't evaluated.
+func (c *Call) Succeeded() (bool, error) {
+	if c.Analysis == nil || c.Analysis.SuccessEvaluation == nil {
+		return false, ErrNoSuccessEvaluation
+	}
+	return c.Analysis.SuccessEvaluation.Passed()
+}
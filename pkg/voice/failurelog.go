@@ -0,0 +1,157 @@
+package voice
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// defaultFailureLogMaxBytes is the size at which a failure log file is rotated when none is configured
+const defaultFailureLogMaxBytes = 10 << 20
+
+// SetFailureLog makes the server write the raw payload of every webhook that
+// fails processing to out, as one JSON object per line with the event type,
+// time and error, so the failure can be reproduced. Successful webhooks
+// aren't written. Pass a RotatingFile, or log.Writer() to send them to the
+// standard logger; nil disables the failure log.
+func (w *WebhookServer) SetFailureLog(out io.Writer) {
+	w.failureLogMu.Lock()
+	defer w.failureLogMu.Unlock()
+	w.failureLog = out
+}
+
+// logFailure writes a failed webhook to the failure log, if one is set
+func (w *WebhookServer) logFailure(payload []byte, processErr error) {
+	w.failureLogMu.Lock()
+	defer w.failureLogMu.Unlock()
+	if w.failureLog == nil {
+		return
+	}
+
+	// The raw payload is enough to reproduce the failure, so skip the parsed copy
+	dump := newWebhookDump(payload, processErr)
+	dump.Parsed = nil
+
+	data, err := json.Marshal(dump)
+	if err != nil {
+		log.Printf("voice: failed to encode failed webhook: %v", err)
+		return
+	}
+	if _, err := w.failureLog.Write(append(data, '\n')); err != nil {
+		log.Printf("voice: failed to write failed webhook: %v", err)
+	}
+}
+
+// RotatingFile is an append-only file that's moved to path.1 once it
+// reaches its size limit, replacing the previous backup
+type RotatingFile struct {
+	path     string
+	maxBytes int64
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	closed bool
+}
+
+// NewRotatingFile opens path for appending, rotating it once it would grow
+// beyond maxBytes. A maxBytes of zero uses 10 MiB.
+func NewRotatingFile(path string, maxBytes int64) (*RotatingFile, error) {
+	if maxBytes <= 0 {
+		maxBytes = defaultFailureLogMaxBytes
+	}
+
+	r := &RotatingFile{path: path, maxBytes: maxBytes}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the file for appending and records its current size
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", r.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat %s: %w", r.path, err)
+	}
+
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write implements io.Writer, rotating the file first if p doesn't fit. A
+// single write is never split across files.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+	if r.file == nil {
+		// A previous rotation couldn't reopen the file; try again
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			if r.file == nil {
+				return 0, err
+			}
+			// Keep appending to the current file rather than losing the entry
+			log.Printf("voice: %v", err)
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate moves the current file to path.1 and starts a new one. If the move
+// fails, the file at path is reopened so writes carry on there.
+func (r *RotatingFile) rotate() error {
+	closeErr := r.file.Close()
+	r.file = nil
+
+	var renameErr error
+	if closeErr == nil {
+		renameErr = os.Rename(r.path, r.path+".1")
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to rotate %s: %w", r.path, closeErr)
+	}
+	if renameErr != nil {
+		return fmt.Errorf("failed to rotate %s: %w", r.path, renameErr)
+	}
+	return nil
+}
+
+// Close closes the file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,73 @@
+package voice
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFileRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "failures.jsonl")
+	file, err := NewRotatingFile(path, 16)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer file.Close()
+
+	for _, line := range []string{"first line\n", "second line\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	assertFileContents(t, path+".1", "first line\n")
+	assertFileContents(t, path, "second line\n")
+}
+
+func TestRotatingFileKeepsWritingWhenRotationFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "failures.jsonl")
+
+	// A non-empty directory where the rotated file should go makes the rename fail
+	if err := os.MkdirAll(filepath.Join(path+".1", "blocker"), 0755); err != nil {
+		t.Fatalf("failed to create blocking directory: %v", err)
+	}
+
+	file, err := NewRotatingFile(path, 16)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	defer file.Close()
+
+	for _, line := range []string{"first line\n", "second line\n", "third line\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q): %v", strings.TrimSpace(line), err)
+		}
+	}
+
+	assertFileContents(t, path, "first line\nsecond line\nthird line\n")
+}
+
+func TestRotatingFileWriteAfterClose(t *testing.T) {
+	file, err := NewRotatingFile(filepath.Join(t.TempDir(), "failures.jsonl"), 0)
+	if err != nil {
+		t.Fatalf("NewRotatingFile: %v", err)
+	}
+	file.Close()
+
+	if _, err := file.Write([]byte("late\n")); err != os.ErrClosed {
+		t.Errorf("Write after Close = %v, want %v", err, os.ErrClosed)
+	}
+}
+
+func assertFileContents(t *testing.T, path, want string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	if string(data) != want {
+		t.Errorf("%s = %q, want %q", filepath.Base(path), data, want)
+	}
+}
@@ -0,0 +1,162 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/heirloomz/vapi-go-library/pkg/events"
+)
+
+const (
+	// healthCheckTimeout bounds each dependency check made by the health endpoint
+	healthCheckTimeout = 5 * time.Second
+	// tokenCheckTTL is how long a token check result is reused by the health endpoint
+	tokenCheckTTL = time.Minute
+)
+
+// ErrInvalidToken is returned by VerifyToken when VAPI rejects the API token
+var ErrInvalidToken = errors.New("VAPI rejected the API token")
+
+// TokenInfo describes an API token accepted by VAPI
+type TokenInfo struct {
+	Valid bool
+	// OrgID is the organization the token belongs to. It's read from the
+	// org's assistants, so it's empty when the org has none.
+	OrgID string
+}
+
+// VerifyToken makes a minimal authenticated request to check that the API
+// token is accepted and report the org it belongs to. It returns an error
+// wrapping ErrInvalidToken when VAPI responds with 401 or 403.
+func (c *Client) VerifyToken(ctx context.Context) (*TokenInfo, error) {
+	endpoint := fmt.Sprintf("%s/assistant?limit=1", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w (status %d)", ErrInvalidToken, resp.StatusCode)
+	default:
+		return nil, responseError(resp, "failed to verify API token")
+	}
+
+	var assistants []struct {
+		OrgID string `json:"orgId"`
+	}
+	if err := c.decodeList(resp, &assistants); err != nil {
+		return nil, fmt.Errorf("failed to decode assistants: %w", err)
+	}
+
+	info := &TokenInfo{Valid: true}
+	if len(assistants) > 0 {
+		info.OrgID = assistants[0].OrgID
+	}
+	return info, nil
+}
+
+// healthResponse is the JSON body returned by the health endpoint
+type healthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]string      `json:"checks"`
+	Events map[string]WebhookStat `json:"events"`
+}
+
+// handleHealthCheck reports whether the event bus is healthy and the VAPI
+// token is accepted, responding 503 when either isn't. VAPI being unreachable
+// is reported but doesn't fail the check, since restarting won't fix it. The
+// response also includes the webhook event counters from Stats.
+func (w *WebhookServer) handleHealthCheck(rw http.ResponseWriter, req *http.Request) {
+	health := healthResponse{
+		Status: "ok",
+		Checks: make(map[string]string),
+		Events: w.Stats(),
+	}
+
+	if checker, ok := w.eventBus.(events.HealthChecker); ok {
+		if err := w.checkEventBus(req.Context(), checker); err != nil {
+			health.Status = "unhealthy"
+			health.Checks["eventBus"] = err.Error()
+		} else {
+			health.Checks["eventBus"] = "ok"
+		}
+	}
+
+	if w.processor != nil && w.processor.client != nil {
+		err := w.checkToken(req.Context())
+		switch {
+		case err == nil:
+			health.Checks["vapiToken"] = "ok"
+		case errors.Is(err, ErrInvalidToken):
+			health.Status = "unhealthy"
+			health.Checks["vapiToken"] = err.Error()
+		default:
+			health.Checks["vapiToken"] = "unknown: " + err.Error()
+		}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if health.Status != "ok" {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		rw.WriteHeader(http.StatusOK)
+	}
+	json.NewEncoder(rw).Encode(health)
+}
+
+// checkEventBus runs the event bus health check, giving up after healthCheckTimeout
+func (w *WebhookServer) checkEventBus(ctx context.Context, checker events.HealthChecker) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- checker.Health()
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("event bus health check timed out: %w", ctx.Err())
+	}
+}
+
+// checkToken verifies the VAPI token, reusing the last result for tokenCheckTTL
+func (w *WebhookServer) checkToken(ctx context.Context) error {
+	w.tokenCheckMu.Lock()
+	defer w.tokenCheckMu.Unlock()
+
+	if !w.tokenCheckedAt.IsZero() && time.Since(w.tokenCheckedAt) < tokenCheckTTL {
+		return w.tokenCheckErr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	_, w.tokenCheckErr = w.processor.client.VerifyToken(ctx)
+	w.tokenCheckedAt = time.Now()
+	return w.tokenCheckErr
+}
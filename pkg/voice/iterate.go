@@ -0,0 +1,190 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// defaultCallPageSize is the number of calls EachCall requests per page
+const defaultCallPageSize = 100
+
+// ErrStopIteration can be returned from an EachCall callback to stop
+// iterating without EachCall returning an error
+var ErrStopIteration = errors.New("stop iteration")
+
+// CallFilter narrows the calls visited by EachCall
+type CallFilter struct {
+	AssistantID   string
+	PhoneNumberID string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// PageSize is the number of calls requested per page, defaults to 100
+	PageSize int
+	// Limit caps the total number of calls visited; zero visits every matching call
+	Limit int
+}
+
+// EachCall calls fn for every call matching filter, newest first. Pages are
+// fetched by creation time and each page is decoded one call at a time, so
+// memory use doesn't grow with the number of calls. Iteration stops at the
+// first error returned by fn, which EachCall returns unless it's ErrStopIteration.
+func (c *Client) EachCall(ctx context.Context, filter *CallFilter, fn func(Call) error) error {
+	pageSize := defaultCallPageSize
+	limit := 0
+	query := url.Values{}
+	if filter != nil {
+		if filter.AssistantID != "" {
+			query.Set("assistantId", filter.AssistantID)
+		}
+		if filter.PhoneNumberID != "" {
+			query.Set("phoneNumberId", filter.PhoneNumberID)
+		}
+		if !filter.CreatedAfter.IsZero() {
+			query.Set("createdAtGt", filter.CreatedAfter.Format(time.RFC3339Nano))
+		}
+		if !filter.CreatedBefore.IsZero() {
+			query.Set("createdAtLt", filter.CreatedBefore.Format(time.RFC3339Nano))
+		}
+		if filter.PageSize > 0 {
+			pageSize = filter.PageSize
+		}
+		limit = filter.Limit
+	}
+	query.Set("limit", strconv.Itoa(pageSize))
+
+	visited := 0
+	for {
+		count := 0
+		var oldest time.Time
+		err := c.streamCalls(ctx, query, func(call Call) error {
+			count++
+			visited++
+			oldest = call.CreatedAt
+			if err := fn(call); err != nil {
+				return err
+			}
+			if limit > 0 && visited >= limit {
+				return ErrStopIteration
+			}
+			return nil
+		})
+		if errors.Is(err, ErrStopIteration) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if count < pageSize || oldest.IsZero() {
+			return nil
+		}
+
+		// Page backwards from the oldest call seen so far
+		query.Set("createdAtLt", oldest.Format(time.RFC3339Nano))
+	}
+}
+
+// streamCalls requests a page of calls and decodes it element by element, calling fn for each
+func (c *Client) streamCalls(ctx context.Context, query url.Values, fn func(Call) error) error {
+	endpoint := fmt.Sprintf("%s/call?%s", c.baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "error listing calls")
+	}
+	if err := checkJSONContentType(resp); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(c.limitBody(resp))
+	found, err := openList(decoder)
+	if err != nil {
+		return fmt.Errorf("failed to decode calls: %w", err)
+	}
+	if !found {
+		return nil
+	}
+
+	for decoder.More() {
+		var call Call
+		if err := decoder.Decode(&call); err != nil {
+			return fmt.Errorf("failed to decode call: %w", err)
+		}
+		if err := fn(call); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing bracket so a truncated body is reported
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("failed to decode calls: %w", err)
+	}
+
+	return nil
+}
+
+// openList advances decoder to just inside the array of a list response,
+// which is either a top-level array or held under "results" in an envelope
+// object. It reports false when an envelope has no or null results, which is
+// treated as an empty list. Keys after the results are not read.
+func openList(decoder *json.Decoder) (bool, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return false, err
+	}
+	if delim, ok := token.(json.Delim); ok && delim == '{' {
+		found := false
+		for !found && decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return false, err
+			}
+			if key == listEnvelopeKey {
+				found = true
+				continue
+			}
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return false, err
+			}
+		}
+		if !found {
+			return false, nil
+		}
+		if token, err = decoder.Token(); err != nil {
+			return false, err
+		}
+		if token == nil {
+			return false, nil
+		}
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return false, fmt.Errorf("expected an array, got %v", token)
+	}
+	return true, nil
+}
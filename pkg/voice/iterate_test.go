@@ -0,0 +1,239 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/heirloomz/vapi-go-library/pkg/httputil"
+)
+
+func TestOpenList(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantFound bool
+		wantErr   bool
+	}{
+		{name: "top-level array", body: `[{"id":"a"}]`, wantFound: true},
+		{name: "envelope", body: `{"results":[{"id":"a"}]}`, wantFound: true},
+		{name: "envelope with keys before results", body: `{"metadata":{"page":{"next":"x"}},"total":1,"results":[{"id":"a"}]}`, wantFound: true},
+		{name: "null results", body: `{"results":null}`},
+		{name: "missing results", body: `{"metadata":{}}`},
+		{name: "empty envelope", body: `{}`},
+		{name: "results not an array", body: `{"results":{"id":"a"}}`, wantErr: true},
+		{name: "scalar", body: `"calls"`, wantErr: true},
+		{name: "empty body", body: ``, wantErr: true},
+		{name: "truncated envelope", body: `{"metadata":{"page":`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := json.NewDecoder(strings.NewReader(tt.body))
+			found, err := openList(decoder)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openList error = %v, want error %v", err, tt.wantErr)
+			}
+			if found != tt.wantFound {
+				t.Errorf("openList found = %v, want %v", found, tt.wantFound)
+			}
+			if !found {
+				return
+			}
+
+			// The decoder is left just inside the array
+			var call Call
+			if err := decoder.Decode(&call); err != nil || call.ID != "a" {
+				t.Errorf("first element = %+v, %v, want call a", call, err)
+			}
+		})
+	}
+}
+
+func TestStreamCallsEnvelopes(t *testing.T) {
+	tests := []struct {
+		name             string
+		body             string
+		maxResponseBytes int64
+		wantIDs          []string
+		wantErr          bool
+		wantTooLarge     bool
+	}{
+		{name: "top-level array", body: `[{"id":"a"},{"id":"b"}]`, wantIDs: []string{"a", "b"}},
+		{name: "envelope", body: `{"results":[{"id":"a"},{"id":"b"}],"metadata":{}}`, wantIDs: []string{"a", "b"}},
+		{name: "empty array", body: `[]`},
+		{name: "null results", body: `{"results":null}`},
+		{name: "missing results", body: `{"metadata":{"total":0}}`},
+		{name: "truncated between calls", body: `[{"id":"a"},`, wantIDs: []string{"a"}, wantErr: true},
+		{name: "truncated before closing bracket", body: `{"results":[{"id":"a"}`, wantIDs: []string{"a"}, wantErr: true},
+		{name: "truncated inside a call", body: `[{"id":"a"},{"id":"b`, wantIDs: []string{"a"}, wantErr: true},
+		{
+			name:             "exceeds MaxResponseBytes",
+			body:             `[{"id":"a"},{"id":"` + strings.Repeat("b", 256) + `"}]`,
+			maxResponseBytes: 64,
+			wantIDs:          []string{"a"},
+			wantErr:          true,
+			wantTooLarge:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := NewClient(&Config{APIToken: "test-token", BaseURL: server.URL, MaxResponseBytes: tt.maxResponseBytes})
+
+			var ids []string
+			err := client.streamCalls(context.Background(), url.Values{}, func(call Call) error {
+				ids = append(ids, call.ID)
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("streamCalls error = %v, want error %v", err, tt.wantErr)
+			}
+			var tooLarge *httputil.ResponseTooLargeError
+			if got := errors.As(err, &tooLarge); got != tt.wantTooLarge {
+				t.Errorf("streamCalls error = %v, want ResponseTooLargeError %v", err, tt.wantTooLarge)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("visited %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestEachCallPageBoundaries(t *testing.T) {
+	base := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		calls     int
+		undated   bool
+		pageSize  int
+		limit     int
+		stopAfter int
+
+		wantVisited  int
+		wantRequests int
+	}{
+		{name: "short last page", calls: 5, pageSize: 2, wantVisited: 5, wantRequests: 3},
+		{name: "exact multiple of the page size", calls: 4, pageSize: 2, wantVisited: 4, wantRequests: 3},
+		{name: "single short page", calls: 1, pageSize: 2, wantVisited: 1, wantRequests: 1},
+		{name: "no calls", calls: 0, pageSize: 2, wantVisited: 0, wantRequests: 1},
+		{name: "limit inside a page", calls: 5, pageSize: 2, limit: 3, wantVisited: 3, wantRequests: 2},
+		{name: "limit at a page boundary", calls: 5, pageSize: 2, limit: 2, wantVisited: 2, wantRequests: 1},
+		{name: "callback stops iteration", calls: 5, pageSize: 2, stopAfter: 3, wantVisited: 3, wantRequests: 2},
+		{name: "calls without creation time", calls: 5, undated: true, pageSize: 2, wantVisited: 2, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Calls newest first, a minute apart
+			calls := make([]Call, tt.calls)
+			for i := range calls {
+				calls[i].ID = fmt.Sprintf("call-%d", i)
+				if !tt.undated {
+					calls[i].CreatedAt = base.Add(-time.Duration(i) * time.Minute)
+				}
+			}
+
+			var cursors []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				cursors = append(cursors, query.Get("createdAtLt"))
+				limit, _ := strconv.Atoi(query.Get("limit"))
+
+				page := []Call{}
+				for _, call := range calls {
+					if lt := query.Get("createdAtLt"); lt != "" {
+						before, err := time.Parse(time.RFC3339Nano, lt)
+						if err != nil {
+							t.Errorf("createdAtLt %q: %v", lt, err)
+						}
+						if !call.CreatedAt.Before(before) {
+							continue
+						}
+					}
+					if len(page) == limit {
+						break
+					}
+					page = append(page, call)
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(page)
+			}))
+			defer server.Close()
+
+			client := NewClient(&Config{APIToken: "test-token", BaseURL: server.URL})
+
+			var visited []string
+			err := client.EachCall(context.Background(), &CallFilter{PageSize: tt.pageSize, Limit: tt.limit}, func(call Call) error {
+				visited = append(visited, call.ID)
+				if tt.stopAfter > 0 && len(visited) == tt.stopAfter {
+					return ErrStopIteration
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("EachCall: %v", err)
+			}
+
+			if len(visited) != tt.wantVisited {
+				t.Errorf("visited %d calls, want %d: %v", len(visited), tt.wantVisited, visited)
+			}
+			for i, id := range visited {
+				if want := fmt.Sprintf("call-%d", i); id != want {
+					t.Errorf("call %d is %s, want %s", i, id, want)
+				}
+			}
+			if len(cursors) != tt.wantRequests {
+				t.Errorf("made %d requests, want %d", len(cursors), tt.wantRequests)
+			}
+
+			// Each later page starts before the oldest call of the previous one
+			for page := 1; page < len(cursors); page++ {
+				want := calls[page*tt.pageSize-1].CreatedAt.Format(time.RFC3339Nano)
+				if cursors[page] != want {
+					t.Errorf("page %d createdAtLt = %q, want %q", page, cursors[page], want)
+				}
+			}
+		})
+	}
+}
+
+func TestEachCallReturnsCallbackError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"id":"a"},{"id":"b"}]`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{APIToken: "test-token", BaseURL: server.URL})
+
+	failure := errors.New("export failed")
+	visited := 0
+	err := client.EachCall(context.Background(), nil, func(Call) error {
+		visited++
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("EachCall error = %v, want the callback's error", err)
+	}
+	if visited != 1 {
+		t.Errorf("visited %d calls after the callback failed, want 1", visited)
+	}
+}
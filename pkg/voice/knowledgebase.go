@@ -0,0 +1,414 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// UploadError reports which files failed to upload, keyed by file path
+type UploadError struct {
+	Failures map[string]error
+	Total    int
+}
+
+// Error implements the error interface
+func (e *UploadError) Error() string {
+	paths := make([]string, 0, len(e.Failures))
+	for path := range e.Failures {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	details := make([]string, 0, len(paths))
+	for _, path := range paths {
+		details = append(details, fmt.Sprintf("%s: %v", path, e.Failures[path]))
+	}
+
+	return fmt.Sprintf("failed to upload %d of %d files: %s", len(e.Failures), e.Total, strings.Join(details, "; "))
+}
+
+// defaultUploadConcurrency is the number of files uploaded at once when none is configured
+const defaultUploadConcurrency = 4
+
+// KnowledgeBaseOptions configures CreateKnowledgeBaseWithOptions
+type KnowledgeBaseOptions struct {
+	// Concurrency is the number of files uploaded at once, 4 if zero
+	Concurrency int
+	// FileIDs are already uploaded files to include in the knowledge base,
+	// e.g. the successful uploads of an earlier partial attempt
+	FileIDs []string
+	// KeepPartialUploads keeps the files that were uploaded when another
+	// upload or the tool creation fails, so only the failures need retrying.
+	// By default they're deleted again.
+	KeepPartialUploads bool
+}
+
+// UploadedFile is a file uploaded for a knowledge base
+type UploadedFile struct {
+	Path string
+	File *File
+}
+
+// FailedUpload is a file that couldn't be uploaded for a knowledge base
+type FailedUpload struct {
+	Path string
+	Err  error
+}
+
+// KnowledgeBaseResult reports the outcome of CreateKnowledgeBaseWithOptions.
+// Succeeded and Failed are in the order of the given paths.
+type KnowledgeBaseResult struct {
+	Tool      *Tool
+	Succeeded []UploadedFile
+	Failed    []FailedUpload
+}
+
+// CreateKnowledgeBaseFromFiles uploads each file and creates a query tool over
+// them. If any upload or the tool creation fails, files uploaded so far are
+// deleted again and an error is returned; failed uploads are reported as an
+// *UploadError.
+func (c *Client) CreateKnowledgeBaseFromFiles(ctx context.Context, paths []string, name, description string) (*Tool, error) {
+	result, err := c.CreateKnowledgeBaseWithOptions(ctx, paths, name, description, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result.Tool, nil
+}
+
+// CreateKnowledgeBaseWithOptions uploads the files with bounded concurrency
+// and creates a query tool over them and opts.FileIDs. Once ctx is cancelled
+// no further uploads are started. The result is returned even on error so the
+// caller can see which files uploaded; failed uploads are also reported as an
+// *UploadError. Unless opts.KeepPartialUploads is set, the files in
+// result.Succeeded have been deleted again when an error is returned.
+func (c *Client) CreateKnowledgeBaseWithOptions(ctx context.Context, paths []string, name, description string, opts *KnowledgeBaseOptions) (*KnowledgeBaseResult, error) {
+	if opts == nil {
+		opts = &KnowledgeBaseOptions{}
+	}
+	if len(paths) == 0 && len(opts.FileIDs) == 0 {
+		return nil, fmt.Errorf("at least one file path is required")
+	}
+
+	result := c.uploadFiles(ctx, paths, opts.Concurrency)
+
+	var uploadedIDs []string
+	for _, uploaded := range result.Succeeded {
+		uploadedIDs = append(uploadedIDs, uploaded.File.ID)
+	}
+
+	if len(result.Failed) > 0 {
+		if !opts.KeepPartialUploads {
+			c.rollbackUploads(uploadedIDs)
+		}
+		failures := make(map[string]error, len(result.Failed))
+		for _, failed := range result.Failed {
+			failures[failed.Path] = failed.Err
+		}
+		return result, &UploadError{Failures: failures, Total: len(paths)}
+	}
+
+	fileIDs := append(append([]string{}, opts.FileIDs...), uploadedIDs...)
+	tool, err := c.CreateQueryToolContext(ctx, fileIDs, name, description)
+	if err != nil {
+		if !opts.KeepPartialUploads {
+			c.rollbackUploads(uploadedIDs)
+		}
+		return result, fmt.Errorf("failed to create knowledge base tool: %w", err)
+	}
+
+	result.Tool = tool
+	return result, nil
+}
+
+// uploadFiles uploads up to concurrency files at once, recording files not
+// started before ctx was cancelled as failed with the context's error
+func (c *Client) uploadFiles(ctx context.Context, paths []string, concurrency int) *KnowledgeBaseResult {
+	if concurrency <= 0 {
+		concurrency = defaultUploadConcurrency
+	}
+
+	files := make([]*File, len(paths))
+	errs := make([]error, len(paths))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, path := range paths {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			files[i], errs[i] = c.UploadFileWithProgress(ctx, path, nil)
+		}(i, path)
+	}
+	wg.Wait()
+
+	result := &KnowledgeBaseResult{}
+	for i, path := range paths {
+		if errs[i] != nil {
+			result.Failed = append(result.Failed, FailedUpload{Path: path, Err: errs[i]})
+			continue
+		}
+		result.Succeeded = append(result.Succeeded, UploadedFile{Path: path, File: files[i]})
+	}
+	return result
+}
+
+// GetKnowledgeBaseFiles returns the files of a knowledge base query tool
+func (c *Client) GetKnowledgeBaseFiles(ctx context.Context, toolID string) ([]File, error) {
+	tool, err := c.GetTool(ctx, toolID)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	seen := make(map[string]bool)
+	for _, kb := range tool.KnowledgeBases {
+		for _, fileID := range kb.FileIDs {
+			if seen[fileID] {
+				continue
+			}
+			seen[fileID] = true
+
+			file, err := c.GetFile(ctx, fileID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get file %s: %w", fileID, err)
+			}
+			files = append(files, *file)
+		}
+	}
+	return files, nil
+}
+
+// RefreshKnowledgeBase replaces the files of a knowledge base query tool: it
+// uploads newPaths, points the tool's knowledge base at them and deletes the
+// files it used before. If an upload or the tool update fails the new files
+// are deleted again and the tool is unchanged. The tool must have exactly one
+// knowledge base, as tools created by CreateQueryTool do.
+func (c *Client) RefreshKnowledgeBase(ctx context.Context, toolID string, newPaths []string) error {
+	if len(newPaths) == 0 {
+		return fmt.Errorf("at least one file path is required")
+	}
+
+	tool, err := c.GetTool(ctx, toolID)
+	if err != nil {
+		return err
+	}
+	if len(tool.KnowledgeBases) != 1 {
+		return fmt.Errorf("tool %s has %d knowledge bases, expected 1", toolID, len(tool.KnowledgeBases))
+	}
+	oldFileIDs := tool.KnowledgeBases[0].FileIDs
+
+	result := c.uploadFiles(ctx, newPaths, 0)
+	var newFileIDs []string
+	for _, uploaded := range result.Succeeded {
+		newFileIDs = append(newFileIDs, uploaded.File.ID)
+	}
+	if len(result.Failed) > 0 {
+		c.rollbackUploads(newFileIDs)
+		failures := make(map[string]error, len(result.Failed))
+		for _, failed := range result.Failed {
+			failures[failed.Path] = failed.Err
+		}
+		return &UploadError{Failures: failures, Total: len(newPaths)}
+	}
+
+	knowledgeBases := tool.KnowledgeBases
+	knowledgeBases[0].FileIDs = newFileIDs
+	if err := c.updateToolKnowledgeBases(ctx, toolID, knowledgeBases); err != nil {
+		c.rollbackUploads(newFileIDs)
+		return err
+	}
+
+	// The tool no longer references the old files, so failing to delete them
+	// only leaks storage; report it without undoing the refresh
+	var errs []error
+	for _, fileID := range oldFileIDs {
+		if err := c.DeleteFile(ctx, fileID); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fileID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("knowledge base refreshed but failed to delete %d old files: %w", len(errs), errors.Join(errs...))
+	}
+	return nil
+}
+
+// updateToolKnowledgeBases replaces the knowledge bases of a query tool
+func (c *Client) updateToolKnowledgeBases(ctx context.Context, toolID string, knowledgeBases []KnowledgeBase) error {
+	payloadBytes, err := json.Marshal(map[string]interface{}{"knowledgeBases": knowledgeBases})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/tool/%s", c.baseURL, toolID)
+	req, err := http.NewRequestWithContext(ctx, "PATCH", endpoint, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return err
+	}
+
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, "failed to update tool")
+	}
+	return nil
+}
+
+// GetTool returns a VAPI tool by ID
+func (c *Client) GetTool(ctx context.Context, toolID string) (*Tool, error) {
+	if toolID == "" {
+		return nil, fmt.Errorf("toolID is required")
+	}
+
+	var tool Tool
+	if err := c.getResource(ctx, fmt.Sprintf("%s/tool/%s", c.baseURL, toolID), "failed to get tool", &tool); err != nil {
+		return nil, err
+	}
+	return &tool, nil
+}
+
+// DeleteTool deletes a tool
+func (c *Client) DeleteTool(ctx context.Context, toolID string) error {
+	if toolID == "" {
+		return fmt.Errorf("toolID is required")
+	}
+
+	endpoint := fmt.Sprintf("%s/tool/%s", c.baseURL, toolID)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return responseError(resp, "failed to delete tool")
+	}
+
+	c.forget(ResourceTool, toolID)
+	return nil
+}
+
+// GetFile returns a VAPI file by ID
+func (c *Client) GetFile(ctx context.Context, fileID string) (*File, error) {
+	if fileID == "" {
+		return nil, fmt.Errorf("fileID is required")
+	}
+
+	var file File
+	if err := c.getResource(ctx, fmt.Sprintf("%s/file/%s", c.baseURL, fileID), "failed to get file", &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// getResource fetches a single resource from endpoint into v
+func (c *Client) getResource(ctx context.Context, endpoint, message string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp, message)
+	}
+	return c.decodeOne(resp, v)
+}
+
+// rollbackUploads deletes uploaded files on a best-effort basis. It doesn't use
+// the caller's context, which may already be cancelled.
+func (c *Client) rollbackUploads(fileIDs []string) {
+	for _, fileID := range fileIDs {
+		c.DeleteFile(context.Background(), fileID)
+	}
+}
+
+// DeleteFile deletes a file from VAPI
+func (c *Client) DeleteFile(ctx context.Context, fileID string) error {
+	if fileID == "" {
+		return fmt.Errorf("fileID is required")
+	}
+
+	endpoint := fmt.Sprintf("%s/file/%s", c.baseURL, fileID)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	// Add headers
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return err
+	}
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return responseError(resp, "failed to delete file")
+	}
+
+	c.forget(ResourceFile, fileID)
+	return nil
+}
@@ -0,0 +1,304 @@
+package voice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrListenNotEnabled is returned by ListenToCall when the call has no listen
+// URL, i.e. its assistant's monitor plan doesn't enable listening or the call
+// has ended
+var ErrListenNotEnabled = errors.New("listening is not enabled for this call")
+
+// webSocketGUID is appended to the handshake key to compute the accept header (RFC 6455)
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// ListenToCall connects to the monitor listen socket of a live call and
+// returns its audio as a stream of raw bytes, in the format VAPI sends it
+// (16-bit PCM by default). The API token is sent with the connection so it
+// also works when the monitor plan enables listen authentication. The stream
+// ends with io.EOF when the call ends; cancelling ctx or calling Close closes
+// the connection.
+func (c *Client) ListenToCall(ctx context.Context, callID string) (io.ReadCloser, error) {
+	call, err := c.GetCallContext(ctx, callID)
+	if err != nil {
+		return nil, err
+	}
+	if call.Monitor == nil || call.Monitor.ListenURL == "" {
+		return nil, ErrListenNotEnabled
+	}
+
+	headers, err := c.getHeadersContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := c.dialWebSocket(ctx, call.Monitor.ListenURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to listen socket: %w", err)
+	}
+
+	stream := &audioStream{ctx: ctx, conn: conn, done: make(chan struct{})}
+	go func() {
+		select {
+		case <-ctx.Done():
+			stream.Close()
+		case <-stream.done:
+		}
+	}()
+	return stream, nil
+}
+
+// dialWebSocket opens a WebSocket connection through the client's transport.
+// The handshake is a plain HTTP/1.1 upgrade, so the transport's TLS, proxy
+// and dial settings apply; the client's Timeout doesn't, as the connection
+// is long-lived.
+func (c *Client) dialWebSocket(ctx context.Context, endpoint string, headers map[string]string) (io.ReadWriteCloser, error) {
+	switch {
+	case strings.HasPrefix(endpoint, "wss://"):
+		endpoint = "https://" + strings.TrimPrefix(endpoint, "wss://")
+	case strings.HasPrefix(endpoint, "ws://"):
+		endpoint = "http://" + strings.TrimPrefix(endpoint, "ws://")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		if key == "Content-Type" {
+			continue
+		}
+		req.Header.Add(key, value)
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+
+	httpClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		defer resp.Body.Close()
+		return nil, responseError(resp, "websocket handshake failed")
+	}
+
+	conn, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, fmt.Errorf("websocket handshake failed: connection is not writable")
+	}
+
+	accept := sha1.Sum([]byte(key + webSocketGUID))
+	if resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(accept[:]) {
+		conn.Close()
+		return nil, fmt.Errorf("websocket handshake failed: invalid Sec-WebSocket-Accept")
+	}
+	return conn, nil
+}
+
+// audioStream reads the binary messages of a listen socket as one byte
+// stream. Text messages, which carry metadata, are skipped and pings are
+// answered.
+type audioStream struct {
+	ctx  context.Context
+	conn io.ReadWriteCloser
+
+	// remaining is the unread payload of the current binary frame
+	remaining uint64
+	mask      [4]byte
+	masked    bool
+	offset    uint64
+	// inBinary reports whether continuation frames belong to a binary message
+	inBinary bool
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// Read implements io.Reader. Once the stream's context is cancelled it
+// returns the context's error.
+func (s *audioStream) Read(p []byte) (int, error) {
+	n, err := s.read(p)
+	if err != nil && s.ctx.Err() != nil {
+		err = s.ctx.Err()
+	}
+	return n, err
+}
+
+// read reads audio from the current binary frame, moving to the next one as needed
+func (s *audioStream) read(p []byte) (int, error) {
+	for s.remaining == 0 {
+		if err := s.nextFrame(); err != nil {
+			return 0, err
+		}
+	}
+
+	if uint64(len(p)) > s.remaining {
+		p = p[:s.remaining]
+	}
+	n, err := s.conn.Read(p)
+	if s.masked {
+		for i := 0; i < n; i++ {
+			p[i] ^= s.mask[(s.offset+uint64(i))%4]
+		}
+	}
+	s.remaining -= uint64(n)
+	s.offset += uint64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// nextFrame reads frame headers until a binary frame with payload is found,
+// handling control frames and discarding text frames on the way
+func (s *audioStream) nextFrame() error {
+	var header [2]byte
+	if _, err := io.ReadFull(s.conn, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return err
+		}
+		// The server closed the connection without a close frame
+		return io.EOF
+	}
+
+	opcode := header[0] & 0x0F
+	final := header[0]&0x80 != 0
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(s.conn, ext[:]); err != nil {
+			return unexpectedEOF(err)
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(s.conn, ext[:]); err != nil {
+			return unexpectedEOF(err)
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(s.conn, mask[:]); err != nil {
+			return unexpectedEOF(err)
+		}
+	}
+
+	switch opcode {
+	case opBinary, opContinuation:
+		if opcode == opBinary {
+			s.inBinary = true
+		}
+		if !s.inBinary {
+			return s.discard(length)
+		}
+		s.inBinary = !final
+		s.remaining, s.mask, s.masked, s.offset = length, mask, masked, 0
+		return nil
+	case opText:
+		s.inBinary = false
+		return s.discard(length)
+	case opPing, opPong, opClose:
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(s.conn, payload); err != nil {
+			return unexpectedEOF(err)
+		}
+		if masked {
+			for i := range payload {
+				payload[i] ^= mask[i%4]
+			}
+		}
+		switch opcode {
+		case opPing:
+			return s.writeFrame(opPong, payload)
+		case opClose:
+			s.Close()
+			return io.EOF
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported websocket opcode %d", opcode)
+	}
+}
+
+// discard skips a frame payload
+func (s *audioStream) discard(length uint64) error {
+	if _, err := io.CopyN(io.Discard, s.conn, int64(length)); err != nil {
+		return unexpectedEOF(err)
+	}
+	return nil
+}
+
+// writeFrame sends a single masked frame, as clients must mask their frames
+func (s *audioStream) writeFrame(opcode byte, payload []byte) error {
+	frame := make([]byte, 0, 6+len(payload))
+	frame = append(frame, 0x80|opcode, 0x80|byte(len(payload)))
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_, err := s.conn.Write(frame)
+	return err
+}
+
+// Close sends a close frame and closes the connection
+func (s *audioStream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		// Best effort: the server may already be gone
+		s.writeFrame(opClose, []byte{0x03, 0xE8})
+		err = s.conn.Close()
+	})
+	return err
+}
+
+// unexpectedEOF turns an io.EOF in the middle of a frame into io.ErrUnexpectedEOF
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package voice
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clientFrame is a frame the listen client sent to the test server
+type clientFrame struct {
+	opcode  byte
+	payload []byte
+}
+
+// newListenServer starts a server that returns a call with a listen URL from
+// GET /call/{id} and lets serve write to the upgraded listen socket. Frames
+// the client sends are delivered on the returned channel, and the socket stays
+// open until the client closes it.
+func newListenServer(t *testing.T, serve func(w *bufio.Writer)) (*Client, <-chan clientFrame) {
+	t.Helper()
+
+	frames := make(chan clientFrame, 16)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/listen" {
+			w.Header().Set("Content-Type", "application/json")
+			listenURL := "ws://" + strings.TrimPrefix(server.URL, "http://") + "/listen"
+			fmt.Fprintf(w, `{"id":%q,"monitor":{"listenUrl":%q}}`, fixtureCallID, listenURL)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("listen socket Authorization = %q, want the API token", r.Header.Get("Authorization"))
+		}
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		accept := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + webSocketGUID))
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n",
+			base64.StdEncoding.EncodeToString(accept[:]))
+		rw.Flush()
+
+		serve(rw.Writer)
+		rw.Flush()
+
+		defer close(frames)
+		for {
+			frame, err := readClientFrame(rw.Reader)
+			if err != nil {
+				return
+			}
+			frames <- frame
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return NewClient(&Config{APIToken: "test-token", BaseURL: server.URL}), frames
+}
+
+// serverFrame encodes an unmasked frame, as servers send them
+func serverFrame(final bool, opcode byte, payload string) []byte {
+	first := opcode
+	if final {
+		first |= 0x80
+	}
+	return append([]byte{first, byte(len(payload))}, payload...)
+}
+
+// readClientFrame reads and unmasks a frame sent by the client
+func readClientFrame(r *bufio.Reader) (clientFrame, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return clientFrame{}, err
+	}
+	if header[1]&0x80 == 0 {
+		return clientFrame{}, errors.New("client frame is not masked")
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return clientFrame{}, err
+	}
+	payload := make([]byte, header[1]&0x7F)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return clientFrame{}, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return clientFrame{opcode: header[0] & 0x0F, payload: payload}, nil
+}
+
+// nextClientFrame waits for the next frame the client sends
+func nextClientFrame(t *testing.T, frames <-chan clientFrame) clientFrame {
+	t.Helper()
+
+	select {
+	case frame, ok := <-frames:
+		if !ok {
+			t.Fatal("listen socket closed before the expected frame")
+		}
+		return frame
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a client frame")
+	}
+	return clientFrame{}
+}
+
+func TestListenToCallReadsAudioFrames(t *testing.T) {
+	client, frames := newListenServer(t, func(w *bufio.Writer) {
+		var stream []byte
+		stream = append(stream, serverFrame(true, opText, `{"type":"metadata"}`)...)
+		// A binary message fragmented around a ping
+		stream = append(stream, serverFrame(false, opBinary, "ab")...)
+		stream = append(stream, serverFrame(true, opPing, "keepalive")...)
+		stream = append(stream, serverFrame(true, opContinuation, "cd")...)
+		stream = append(stream, serverFrame(true, opText, `{"type":"speech"}`)...)
+		stream = append(stream, serverFrame(true, opBinary, "ef")...)
+		stream = append(stream, serverFrame(true, opClose, "\x03\xe8")...)
+		w.Write(stream)
+	})
+
+	stream, err := client.ListenToCall(context.Background(), fixtureCallID)
+	if err != nil {
+		t.Fatalf("ListenToCall: %v", err)
+	}
+	defer stream.Close()
+
+	audio, err := io.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(audio) != "abcdef" {
+		t.Errorf("audio = %q, want %q", audio, "abcdef")
+	}
+
+	if frame := nextClientFrame(t, frames); frame.opcode != opPong || string(frame.payload) != "keepalive" {
+		t.Errorf("client answered the ping with opcode %d %q, want a pong echoing it", frame.opcode, frame.payload)
+	}
+	if frame := nextClientFrame(t, frames); frame.opcode != opClose {
+		t.Errorf("client sent opcode %d after the server's close frame, want a close frame", frame.opcode)
+	}
+}
+
+func TestListenToCallContextCancel(t *testing.T) {
+	client, frames := newListenServer(t, func(w *bufio.Writer) {
+		w.Write(serverFrame(true, opBinary, "ab"))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.ListenToCall(ctx, fixtureCallID)
+	if err != nil {
+		t.Fatalf("ListenToCall: %v", err)
+	}
+	defer stream.Close()
+
+	buf := make([]byte, 2)
+	if _, err := io.ReadFull(stream, buf); err != nil {
+		t.Fatalf("reading the first frame: %v", err)
+	}
+
+	cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := stream.Read(buf)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Read after cancel = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Read did not return after the context was cancelled")
+	}
+
+	if frame := nextClientFrame(t, frames); frame.opcode != opClose {
+		t.Errorf("client sent opcode %d on cancel, want a close frame", frame.opcode)
+	}
+}
@@ -0,0 +1,113 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultLookupLimit      = 100
+	defaultLookupMaxScanned = 1000
+	lookupPageSize          = 100
+)
+
+// CallLookupOptions narrows the calls returned by ListCallsByPhoneNumber
+type CallLookupOptions struct {
+	AssistantID string
+	// PhoneNumberID restricts the lookup to calls on one of your VAPI numbers
+	PhoneNumberID string
+	// Limit caps the number of matching calls returned, defaults to 100
+	Limit int
+	// MaxScanned caps the number of calls fetched from VAPI while filtering, defaults to 1000
+	MaxScanned int
+}
+
+// ListCallsByPhoneNumber returns calls where number is either the customer's
+// number or the VAPI number, newest first. VAPI can't filter calls by customer
+// number server-side, so calls are paged by creation time and filtered
+// client-side, stopping once opts.Limit matches or opts.MaxScanned calls have
+// been fetched. Numbers are compared on their digits only.
+func (c *Client) ListCallsByPhoneNumber(ctx context.Context, number string, opts *CallLookupOptions) ([]Call, error) {
+	target := normalizePhoneNumber(number)
+	if target == "" {
+		return nil, fmt.Errorf("phone number is required")
+	}
+
+	limit := defaultLookupLimit
+	maxScanned := defaultLookupMaxScanned
+	query := url.Values{}
+	if opts != nil {
+		if opts.AssistantID != "" {
+			query.Set("assistantId", opts.AssistantID)
+		}
+		if opts.PhoneNumberID != "" {
+			query.Set("phoneNumberId", opts.PhoneNumberID)
+		}
+		if opts.Limit > 0 {
+			limit = opts.Limit
+		}
+		if opts.MaxScanned > 0 {
+			maxScanned = opts.MaxScanned
+		}
+	}
+	query.Set("limit", strconv.Itoa(lookupPageSize))
+
+	var matches []Call
+	scanned := 0
+	for scanned < maxScanned {
+		page, err := c.listCalls(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, call := range page {
+			if call.involvesNumber(target) {
+				matches = append(matches, call)
+				if len(matches) >= limit {
+					return matches, nil
+				}
+			}
+		}
+
+		scanned += len(page)
+		if len(page) < lookupPageSize {
+			break
+		}
+
+		// Page backwards from the oldest call seen so far
+		oldest := page[len(page)-1].CreatedAt
+		if oldest.IsZero() {
+			break
+		}
+		query.Set("createdAtLt", oldest.Format(time.RFC3339Nano))
+	}
+
+	return matches, nil
+}
+
+// involvesNumber returns whether the call's customer or VAPI number matches the normalized number
+func (c *Call) involvesNumber(normalized string) bool {
+	if c.Customer != nil {
+		if normalizePhoneNumber(c.Customer.Number) == normalized || normalizePhoneNumber(c.Customer.Phone) == normalized {
+			return true
+		}
+	}
+	if c.PhoneNumber != nil && normalizePhoneNumber(c.PhoneNumber.Number) == normalized {
+		return true
+	}
+	return false
+}
+
+// normalizePhoneNumber strips everything but digits so "+1 (555) 010-0000" matches "+15550100000"
+func normalizePhoneNumber(number string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, number)
+}
@@ -0,0 +1,163 @@
+package voice
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Webhook message types sent by VAPI
+const (
+	MessageTypeEndOfCallReport  = "end-of-call-report"
+	MessageTypeStatusUpdate     = "status-update"
+	MessageTypeTranscript       = "transcript"
+	MessageTypeToolCalls        = "tool-calls"
+	MessageTypeAssistantRequest = "assistant-request"
+	MessageTypeHang             = "hang"
+	MessageTypeSpeechUpdate     = "speech-update"
+)
+
+// WebhookMessage represents a typed VAPI webhook message
+type WebhookMessage interface {
+	MessageType() string
+}
+
+// StatusUpdateMessage represents a status-update message
+type StatusUpdateMessage struct {
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+	EndedReason string `json:"endedReason,omitempty"`
+	Call        *Call  `json:"call,omitempty"`
+}
+
+// TranscriptMessage represents a transcript message
+type TranscriptMessage struct {
+	Type           string `json:"type"`
+	Role           string `json:"role"`
+	TranscriptType string `json:"transcriptType"`
+	Transcript     string `json:"transcript"`
+	Call           *Call  `json:"call,omitempty"`
+}
+
+// ToolCallsMessage represents a tool-calls message
+type ToolCallsMessage struct {
+	Type         string     `json:"type"`
+	ToolCallList []ToolCall `json:"toolCallList"`
+	Call         *Call      `json:"call,omitempty"`
+}
+
+// ToolCall represents a single tool invocation requested by the assistant
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction represents the function called in a tool call
+type ToolCallFunction struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+}
+
+// AssistantRequestMessage represents an assistant-request message
+type AssistantRequestMessage struct {
+	Type        string       `json:"type"`
+	Call        *Call        `json:"call,omitempty"`
+	Customer    *Customer    `json:"customer,omitempty"`
+	PhoneNumber *PhoneNumber `json:"phoneNumber,omitempty"`
+}
+
+// HangMessage represents a hang message
+type HangMessage struct {
+	Type string `json:"type"`
+	Call *Call  `json:"call,omitempty"`
+}
+
+// SpeechUpdateMessage represents a speech-update message
+type SpeechUpdateMessage struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Role   string `json:"role"`
+	Call   *Call  `json:"call,omitempty"`
+}
+
+// UnknownWebhookMessage holds a message whose type has no typed struct
+type UnknownWebhookMessage struct {
+	Type string          `json:"type"`
+	Raw  json.RawMessage `json:"-"`
+}
+
+// MessageType returns the webhook message type
+func (m *EndOfCallReport) MessageType() string { return MessageTypeEndOfCallReport }
+
+// MessageType returns the webhook message type
+func (m *StatusUpdateMessage) MessageType() string { return MessageTypeStatusUpdate }
+
+// MessageType returns the webhook message type
+func (m *TranscriptMessage) MessageType() string { return MessageTypeTranscript }
+
+// MessageType returns the webhook message type
+func (m *ToolCallsMessage) MessageType() string { return MessageTypeToolCalls }
+
+// MessageType returns the webhook message type
+func (m *AssistantRequestMessage) MessageType() string { return MessageTypeAssistantRequest }
+
+// MessageType returns the webhook message type
+func (m *HangMessage) MessageType() string { return MessageTypeHang }
+
+// MessageType returns the webhook message type
+func (m *SpeechUpdateMessage) MessageType() string { return MessageTypeSpeechUpdate }
+
+// MessageType returns the webhook message type
+func (m *UnknownWebhookMessage) MessageType() string { return m.Type }
+
+// ParseWebhookMessage parses a VAPI webhook payload into a typed message.
+// The payload may be the full webhook body or just its "message" object.
+func ParseWebhookMessage(payload []byte) (WebhookMessage, error) {
+	var envelope struct {
+		Message json.RawMessage `json:"message"`
+	}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+
+	raw := payload
+	if len(envelope.Message) > 0 && string(envelope.Message) != "null" {
+		raw = envelope.Message
+	}
+
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook message: %w", err)
+	}
+	if header.Type == "" {
+		return nil, fmt.Errorf("webhook message has no type")
+	}
+
+	var message WebhookMessage
+	switch header.Type {
+	case MessageTypeEndOfCallReport:
+		message = &EndOfCallReport{}
+	case MessageTypeStatusUpdate:
+		message = &StatusUpdateMessage{}
+	case MessageTypeTranscript:
+		message = &TranscriptMessage{}
+	case MessageTypeToolCalls:
+		message = &ToolCallsMessage{}
+	case MessageTypeAssistantRequest:
+		message = &AssistantRequestMessage{}
+	case MessageTypeHang:
+		message = &HangMessage{}
+	case MessageTypeSpeechUpdate:
+		message = &SpeechUpdateMessage{}
+	default:
+		return &UnknownWebhookMessage{Type: header.Type, Raw: raw}, nil
+	}
+
+	if err := json.Unmarshal(raw, message); err != nil {
+		return nil, fmt.Errorf("failed to parse %s message: %w", header.Type, err)
+	}
+
+	return message, nil
+}
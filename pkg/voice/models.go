@@ -2,6 +2,8 @@ package voice
 
 import (
 	"time"
+
+	"github.com/heirloomz/vapi-go-library/pkg/chat"
 )
 
 // Assistant represents a VAPI assistant
@@ -10,33 +12,82 @@ type Assistant struct {
 	Name         string    `json:"name"`
 	SystemPrompt string    `json:"systemPrompt,omitempty"`
 	CreatedAt    time.Time `json:"createdAt"`
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// FullAssistant is an assistant with its complete configuration, as returned
+// by the API, alongside the identifying fields chat.Assistant doesn't carry
+type FullAssistant struct {
+	ID        string    `json:"id"`
+	OrgID     string    `json:"orgId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	chat.Assistant
+}
+
+// AssistantListOptions narrows the assistants returned by ListFullAssistants
+type AssistantListOptions struct {
+	// Limit caps the number of assistants returned; zero uses the API default
+	Limit int
 }
 
 // Call represents a call made through VAPI
 type Call struct {
-	ID           string      `json:"id"`
-	AssistantID  string      `json:"assistantId"`
-	Status       string      `json:"status"`
-	Duration     int         `json:"duration"`
-	CreatedAt    time.Time   `json:"createdAt"`
-	Customer     *Customer   `json:"customer,omitempty"`
-	Analysis     *Analysis   `json:"analysis,omitempty"`
-	Artifacts    []Artifact  `json:"artifacts,omitempty"`
-	Transcript   interface{} `json:"transcript,omitempty"` // Can be []Message or string
-	Messages     []Message   `json:"messages,omitempty"`
-	Conversation []Message   `json:"conversation,omitempty"`
+	ID           string        `json:"id"`
+	AssistantID  string        `json:"assistantId"`
+	Status       string        `json:"status"`
+	EndedReason  string        `json:"endedReason,omitempty"`
+	Duration     int           `json:"duration"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	Customer     *Customer     `json:"customer,omitempty"`
+	PhoneNumber  *PhoneNumber  `json:"phoneNumber,omitempty"`
+	Analysis     *Analysis     `json:"analysis,omitempty"`
+	Artifacts    []Artifact    `json:"artifacts,omitempty"`
+	Transcript   interface{}   `json:"transcript,omitempty"` // Can be []Message or string
+	Messages     []Message     `json:"messages,omitempty"`
+	Conversation []Message     `json:"conversation,omitempty"`
+	SchedulePlan *SchedulePlan `json:"schedulePlan,omitempty"`
+	Cost         float64       `json:"cost,omitempty"`
+	Costs        []chat.Cost   `json:"costs,omitempty"`
+
+	Summary            string `json:"summary,omitempty"`
+	RecordingURL       string `json:"recordingUrl,omitempty"`
+	StereoRecordingURL string `json:"stereoRecordingUrl,omitempty"`
+
+	Monitor *CallMonitor `json:"monitor,omitempty"`
+
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// CallMonitor holds the URLs for monitoring a live call, set when the
+// assistant's monitor plan enables listening or control
+type CallMonitor struct {
+	ListenURL  string `json:"listenUrl,omitempty"`
+	ControlURL string `json:"controlUrl,omitempty"`
+}
+
+// SchedulePlan represents when a scheduled call should be placed
+type SchedulePlan struct {
+	EarliestAt time.Time  `json:"earliestAt"`
+	LatestAt   *time.Time `json:"latestAt,omitempty"`
 }
 
 // Customer represents a customer in a VAPI call
 type Customer struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Phone string `json:"phone"`
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Phone  string `json:"phone,omitempty"`
+	Number string `json:"number,omitempty"`
 }
 
 // Analysis represents the analysis of a VAPI call
 type Analysis struct {
-	Transcript []Message `json:"transcript,omitempty"`
+	Transcript     []Message              `json:"transcript,omitempty"`
+	Summary        string                 `json:"summary,omitempty"`
+	StructuredData map[string]interface{} `json:"structuredData,omitempty"`
+
+	SuccessEvaluation *SuccessEvaluation `json:"successEvaluation,omitempty"`
 }
 
 // Artifact represents an artifact from a VAPI call
@@ -101,13 +152,42 @@ type WebhookEvent struct {
 
 // EndOfCallReport represents an end-of-call-report event
 type EndOfCallReport struct {
-	Type        string    `json:"type"`
-	Call        Call      `json:"call"`
-	Transcript  []Message `json:"transcript"`
-	Summary     string    `json:"summary,omitempty"`
-	Analysis    *Analysis `json:"analysis,omitempty"`
-	AssistantID string    `json:"assistantId"`
-	CallID      string    `json:"callId"`
+	Type         string      `json:"type"`
+	EndedReason  string      `json:"endedReason,omitempty"`
+	Call         Call        `json:"call"`
+	Transcript   interface{} `json:"transcript,omitempty"`
+	Messages     []Message   `json:"messages,omitempty"`
+	RecordingURL string      `json:"recordingUrl,omitempty"`
+	Cost         float64     `json:"cost,omitempty"`
+	DurationSecs float64     `json:"durationSeconds,omitempty"`
+	Summary      string      `json:"summary,omitempty"`
+	Analysis     *Analysis   `json:"analysis,omitempty"`
+	AssistantID  string      `json:"assistantId,omitempty"`
+	CallID       string      `json:"callId,omitempty"`
+}
+
+// GetCallID returns the call ID from the embedded call, falling back to the top-level field
+func (r *EndOfCallReport) GetCallID() string {
+	if r.Call.ID != "" {
+		return r.Call.ID
+	}
+	return r.CallID
+}
+
+// GetAssistantID returns the assistant ID from the embedded call, falling back to the top-level field
+func (r *EndOfCallReport) GetAssistantID() string {
+	if r.Call.AssistantID != "" {
+		return r.Call.AssistantID
+	}
+	return r.AssistantID
+}
+
+// CostSummary represents the cost of a call broken down by cost type
+type CostSummary struct {
+	CallID string             `json:"callId"`
+	Total  float64            `json:"total"`
+	ByType map[string]float64 `json:"byType"`
+	Items  []chat.Cost        `json:"items"`
 }
 
 // ProcessedCall represents a processed call stored in the database
@@ -118,8 +198,59 @@ type ProcessedCall struct {
 	Transcript  []Message `json:"transcript"`
 	Duration    int       `json:"duration"`
 	Status      string    `json:"status"`
+	EndedReason string    `json:"ended_reason,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Analysis results, empty when the call has no analysis
+	Summary        string                 `json:"summary,omitempty"`
+	StructuredData map[string]interface{} `json:"structured_data,omitempty"`
+
+	// Metadata set on the call when it was created
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// CallCompletedPayloadVersion is bumped whenever CallCompletedPayload changes incompatibly
+const CallCompletedPayloadVersion = 1
+
+// CallCompletedPayload is the data of every events.EventCallCompleted event.
+// Decode it with events.DecodeData[voice.CallCompletedPayload]; fields are only
+// added between versions, and removals or type changes bump Version.
+type CallCompletedPayload struct {
+	Version        int                    `json:"version"`
+	ID             string                 `json:"id"`
+	CallID         string                 `json:"call_id"`
+	AssistantID    string                 `json:"assistant_id"`
+	Transcript     []Message              `json:"transcript"`
+	Summary        string                 `json:"summary,omitempty"`
+	StructuredData map[string]interface{} `json:"structured_data,omitempty"`
+	Duration       int                    `json:"duration"`
+	Status         string                 `json:"status"`
+	EndedReason    string                 `json:"ended_reason,omitempty"`
+	Cost           float64                `json:"cost"`
+	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+}
+
+// NewCallCompletedPayload builds the call-completed payload for a processed call
+func NewCallCompletedPayload(processedCall *ProcessedCall, cost float64) *CallCompletedPayload {
+	return &CallCompletedPayload{
+		Version:        CallCompletedPayloadVersion,
+		ID:             processedCall.ID,
+		CallID:         processedCall.CallID,
+		AssistantID:    processedCall.AssistantID,
+		Transcript:     processedCall.Transcript,
+		Summary:        processedCall.Summary,
+		StructuredData: processedCall.StructuredData,
+		Duration:       processedCall.Duration,
+		Status:         processedCall.Status,
+		EndedReason:    processedCall.EndedReason,
+		Cost:           cost,
+		Metadata:       processedCall.Metadata,
+		CreatedAt:      processedCall.CreatedAt,
+		UpdatedAt:      processedCall.UpdatedAt,
+	}
 }
 
 // UpdateAssistantRequest represents a request to update an assistant
@@ -129,6 +260,20 @@ type UpdateAssistantRequest struct {
 	ServerURL    *string `json:"serverUrl,omitempty"`
 }
 
+// CreateCallRequest represents a request to place an outbound phone call.
+// Exactly one of AssistantID, Assistant, or SquadID must be set.
+type CreateCallRequest struct {
+	Name               string                   `json:"name,omitempty"`
+	AssistantID        string                   `json:"assistantId,omitempty"`
+	Assistant          *chat.Assistant          `json:"assistant,omitempty"`
+	AssistantOverrides *chat.AssistantOverrides `json:"assistantOverrides,omitempty"`
+	SquadID            string                   `json:"squadId,omitempty"`
+	PhoneNumberID      string                   `json:"phoneNumberId"`
+	Customer           *Customer                `json:"customer"`
+	SchedulePlan       *SchedulePlan            `json:"schedulePlan,omitempty"`
+	Metadata           map[string]interface{}   `json:"metadata,omitempty"`
+}
+
 // CreateToolRequest represents a request to create a tool
 type CreateToolRequest struct {
 	Type           string          `json:"type"`
@@ -0,0 +1,82 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WaitOptions configures how WaitForCall polls a call
+type WaitOptions struct {
+	// Interval is the delay before the first re-poll (default 2s)
+	Interval time.Duration
+	// MaxInterval caps the delay between polls (default 30s)
+	MaxInterval time.Duration
+	// Multiplier grows the interval after each poll; values <= 1 poll at a fixed interval
+	Multiplier float64
+	// MaxWait bounds the total wait; zero waits until ctx is done
+	MaxWait time.Duration
+}
+
+// withDefaults returns a copy of the options with defaults applied
+func (o *WaitOptions) withDefaults() WaitOptions {
+	opts := WaitOptions{}
+	if o != nil {
+		opts = *o
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = 30 * time.Second
+	}
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = opts.Interval
+	}
+	return opts
+}
+
+// WaitForCall polls a call until it reaches a terminal status and returns it.
+// It stops early when ctx is cancelled or MaxWait elapses.
+func (c *Client) WaitForCall(ctx context.Context, callID string, opts *WaitOptions) (*Call, error) {
+	if callID == "" {
+		return nil, fmt.Errorf("callID is required")
+	}
+
+	o := opts.withDefaults()
+	if o.MaxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.MaxWait)
+		defer cancel()
+	}
+
+	interval := o.Interval
+	for {
+		call, err := c.GetCallContext(ctx, callID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("timed out waiting for call %s: %w", callID, ctx.Err())
+			}
+			return nil, fmt.Errorf("failed to poll call %s: %w", callID, err)
+		}
+
+		if call.IsTerminal() {
+			return call, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("timed out waiting for call %s (last status %q): %w", callID, call.Status, ctx.Err())
+		case <-timer.C:
+		}
+
+		if o.Multiplier > 1 {
+			interval = time.Duration(float64(interval) * o.Multiplier)
+			if interval > o.MaxInterval {
+				interval = o.MaxInterval
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package voice
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/heirloomz/vapi-go-library/pkg/httputil"
+)
+
+// rateLimitTracker records the rate limit headers of every VAPI response
+type rateLimitTracker struct {
+	base http.RoundTripper
+
+	mu   sync.Mutex
+	last *httputil.RateLimitInfo
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitTracker) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if info, ok := httputil.ParseRateLimit(resp.Header, time.Now()); ok {
+		t.mu.Lock()
+		t.last = info
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// CloseIdleConnections closes idle connections of the wrapped transport, so
+// Client.Close keeps working through the wrapper
+func (t *rateLimitTracker) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// latest returns a copy of the most recently recorded rate limit state
+func (t *rateLimitTracker) latest() *httputil.RateLimitInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.last == nil {
+		return nil
+	}
+	info := *t.last
+	return &info
+}
+
+// LastRateLimit returns the rate limit state reported by the most recent VAPI
+// response that carried rate limit headers, or nil if none has yet. Use it to
+// pace batch jobs as Remaining approaches zero.
+func (c *Client) LastRateLimit() *httputil.RateLimitInfo {
+	return c.rateLimits.latest()
+}
@@ -0,0 +1,70 @@
+package voice
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ScheduledCallFilter narrows the scheduled calls returned by ListScheduledCalls
+type ScheduledCallFilter struct {
+	AssistantID string
+	// Limit caps the number of calls fetched from VAPI before filtering
+	Limit int
+	// Before, if set, only returns calls scheduled to start before this time
+	Before time.Time
+}
+
+// ListScheduledCalls returns calls that are scheduled but not yet placed.
+// VAPI models these as regular calls with a "scheduled" status and a
+// schedulePlan, so calls are listed and filtered on those fields.
+func (c *Client) ListScheduledCalls(ctx context.Context, filter *ScheduledCallFilter) ([]Call, error) {
+	query := url.Values{}
+	if filter != nil {
+		if filter.AssistantID != "" {
+			query.Set("assistantId", filter.AssistantID)
+		}
+		if filter.Limit > 0 {
+			query.Set("limit", strconv.Itoa(filter.Limit))
+		}
+	}
+
+	calls, err := c.listCalls(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var scheduled []Call
+	for _, call := range calls {
+		if call.Status != CallStatusScheduled {
+			continue
+		}
+		if filter != nil && !filter.Before.IsZero() &&
+			call.SchedulePlan != nil && !call.SchedulePlan.EarliestAt.Before(filter.Before) {
+			continue
+		}
+		scheduled = append(scheduled, call)
+	}
+
+	return scheduled, nil
+}
+
+// CancelScheduledCall cancels a scheduled call by deleting it. Calls that have
+// already been placed can't be cancelled.
+func (c *Client) CancelScheduledCall(ctx context.Context, callID string) error {
+	if callID == "" {
+		return fmt.Errorf("callID is required")
+	}
+
+	call, err := c.GetCallContext(ctx, callID)
+	if err != nil {
+		return err
+	}
+	if call.Status != CallStatusScheduled {
+		return fmt.Errorf("call %s is not scheduled (status %q)", callID, call.Status)
+	}
+
+	return c.DeleteCall(ctx, callID)
+}
@@ -0,0 +1,116 @@
+package voice
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// unknownEventType is the stats key for webhooks whose message type is missing
+// or not one of the MessageType constants
+const unknownEventType = "unknown"
+
+// WebhookStat counts webhook events of one type since the server was created
+type WebhookStat struct {
+	Received  uint64 `json:"received"`
+	Processed uint64 `json:"processed"`
+	Failed    uint64 `json:"failed"`
+}
+
+// webhookStats holds per-event-type webhook counters
+type webhookStats struct {
+	mu     sync.Mutex
+	byType map[string]*WebhookStat
+}
+
+// record counts a received webhook event and whether it was processed
+// successfully. Message types come from webhook payloads, so anything that
+// isn't a known type is counted under "unknown" to keep the map bounded.
+func (s *webhookStats) record(eventType string, err error) {
+	if _, known := messageEventTypes[eventType]; !known {
+		eventType = unknownEventType
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.byType == nil {
+		s.byType = make(map[string]*WebhookStat)
+	}
+	stat, ok := s.byType[eventType]
+	if !ok {
+		stat = &WebhookStat{}
+		s.byType[eventType] = stat
+	}
+
+	stat.Received++
+	if err != nil {
+		stat.Failed++
+	} else {
+		stat.Processed++
+	}
+}
+
+// snapshot returns a copy of the counters
+func (s *webhookStats) snapshot() map[string]WebhookStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make(map[string]WebhookStat, len(s.byType))
+	for eventType, stat := range s.byType {
+		stats[eventType] = *stat
+	}
+	return stats
+}
+
+// Stats returns the number of webhook events received, processed and failed
+// per message type since the server was created. Events whose message type
+// is missing or not one of the MessageType constants are counted under "unknown".
+func (w *WebhookServer) Stats() map[string]WebhookStat {
+	return w.stats.snapshot()
+}
+
+// EnableMetrics serves the webhook counters from Stats in Prometheus text
+// format at path when the server starts. An empty path disables the route.
+func (w *WebhookServer) EnableMetrics(path string) {
+	w.metricsPath = path
+}
+
+// webhookMetrics are the Prometheus counters exposed for each webhook event type
+var webhookMetrics = []struct {
+	name  string
+	help  string
+	value func(WebhookStat) uint64
+}{
+	{"vapi_webhook_events_received_total", "Webhook events received, by message type.", func(s WebhookStat) uint64 { return s.Received }},
+	{"vapi_webhook_events_processed_total", "Webhook events processed successfully, by message type.", func(s WebhookStat) uint64 { return s.Processed }},
+	{"vapi_webhook_events_failed_total", "Webhook events that failed processing, by message type.", func(s WebhookStat) uint64 { return s.Failed }},
+}
+
+// metricEventTypes returns the label values exported for webhook counters:
+// the known message types and "unknown", so the series set is fixed
+func metricEventTypes() []string {
+	eventTypes := make([]string, 0, len(messageEventTypes)+1)
+	for messageType := range messageEventTypes {
+		eventTypes = append(eventTypes, messageType)
+	}
+	sort.Strings(eventTypes)
+	return append(eventTypes, unknownEventType)
+}
+
+// handleMetrics writes the webhook counters in Prometheus text format, with
+// a series for every known message type even before it's been received
+func (w *WebhookServer) handleMetrics(rw http.ResponseWriter, req *http.Request) {
+	stats := w.Stats()
+	eventTypes := metricEventTypes()
+
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	for _, metric := range webhookMetrics {
+		fmt.Fprintf(rw, "# HELP %s %s\n", metric.name, metric.help)
+		fmt.Fprintf(rw, "# TYPE %s counter\n", metric.name)
+		for _, eventType := range eventTypes {
+			fmt.Fprintf(rw, "%s{type=\"%s\"} %d\n", metric.name, eventType, metric.value(stats[eventType]))
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package voice
+
+import "strings"
+
+// Call status values reported by VAPI
+const (
+	CallStatusScheduled  = "scheduled"
+	CallStatusQueued     = "queued"
+	CallStatusRinging    = "ringing"
+	CallStatusInProgress = "in-progress"
+	CallStatusForwarding = "forwarding"
+	CallStatusEnded      = "ended"
+)
+
+// Common ended reasons reported by VAPI. Provider-specific error reasons
+// (e.g. "pipeline-error-openai-llm-failed") are reported as-is.
+const (
+	EndedReasonCustomerEndedCall      = "customer-ended-call"
+	EndedReasonAssistantEndedCall     = "assistant-ended-call"
+	EndedReasonAssistantSaidEndPhrase = "assistant-said-end-call-phrase"
+	EndedReasonAssistantForwarded     = "assistant-forwarded-call"
+	EndedReasonCustomerBusy           = "customer-busy"
+	EndedReasonCustomerDidNotAnswer   = "customer-did-not-answer"
+	EndedReasonVoicemail              = "voicemail"
+	EndedReasonSilenceTimedOut        = "silence-timed-out"
+	EndedReasonExceededMaxDuration    = "exceeded-max-duration"
+	EndedReasonManuallyCanceled       = "manually-canceled"
+)
+
+// IsTerminal returns whether the call has finished and won't change status again
+func (c *Call) IsTerminal() bool {
+	return c.Status == CallStatusEnded
+}
+
+// IsActive returns whether the call is ringing, in progress, or being forwarded
+func (c *Call) IsActive() bool {
+	switch c.Status {
+	case CallStatusRinging, CallStatusInProgress, CallStatusForwarding:
+		return true
+	default:
+		return false
+	}
+}
+
+// Failed returns whether the call ended because of an error, based on its ended reason
+func (c *Call) Failed() bool {
+	if !c.IsTerminal() {
+		return false
+	}
+
+	reason := strings.ToLower(c.EndedReason)
+	return strings.Contains(reason, "error") || strings.Contains(reason, "failed")
+}
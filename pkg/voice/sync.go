@@ -0,0 +1,317 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Assistant sync actions reported in AssistantSyncResult
+const (
+	SyncActionCreated   = "created"
+	SyncActionUpdated   = "updated"
+	SyncActionUnchanged = "unchanged"
+	SyncActionDeleted   = "deleted"
+	SyncActionFailed    = "failed"
+)
+
+// SyncOptions controls how SyncAssistants reconciles assistants
+type SyncOptions struct {
+	// DryRun reports what would change without calling the API for writes
+	DryRun bool
+	// DeleteOrphans deletes VAPI assistants that don't match any config file.
+	// Orphans are left alone if any config file fails to load.
+	DeleteOrphans bool
+}
+
+// AssistantSyncResult is the outcome of syncing a single assistant
+type AssistantSyncResult struct {
+	File        string
+	Name        string
+	AssistantID string
+	Action      string
+	// ChangedFields lists the top-level config fields that differ from VAPI
+	ChangedFields []string
+	Err           error
+}
+
+// SyncResult is the outcome of SyncAssistants
+type SyncResult struct {
+	DryRun  bool
+	Results []AssistantSyncResult
+}
+
+// Failed returns the results of assistants that failed to sync
+func (r *SyncResult) Failed() []AssistantSyncResult {
+	var failed []AssistantSyncResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// LoadAssistantConfig reads an assistant config from a YAML or JSON file
+func LoadAssistantConfig(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read assistant config: %w", err)
+	}
+
+	// YAML is a superset of JSON, so one decoder handles both
+	var assistantConfig map[string]interface{}
+	if err := yaml.Unmarshal(data, &assistantConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse assistant config %s: %w", path, err)
+	}
+	if assistantConfig == nil {
+		return nil, fmt.Errorf("assistant config %s is empty", path)
+	}
+
+	return assistantConfig, nil
+}
+
+// SyncAssistants reconciles VAPI assistants with the config files (.yaml,
+// .yml or .json) in dir. Each file is matched to an existing assistant by
+// metadata.id, or by name when it has no metadata.id; unmatched files are
+// created and matched ones are updated when any field in the file differs.
+// Per-assistant failures are recorded in the result and reported as an error
+// once every file has been processed.
+func (c *Client) SyncAssistants(ctx context.Context, dir string, opts SyncOptions) (*SyncResult, error) {
+	files, err := assistantConfigFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := c.ListAssistantsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assistants: %w", err)
+	}
+
+	result := &SyncResult{DryRun: opts.DryRun}
+	matched := make(map[string]bool)
+	unmatchedFailures := false
+
+	for _, file := range files {
+		entry := c.syncAssistantFile(ctx, file, remote, opts)
+		if entry.AssistantID != "" {
+			matched[entry.AssistantID] = true
+		} else if entry.Err != nil {
+			unmatchedFailures = true
+		}
+		result.Results = append(result.Results, entry)
+	}
+
+	// A file that failed to load may define an assistant that would
+	// otherwise look orphaned, so nothing is deleted in that case
+	if opts.DeleteOrphans && !unmatchedFailures {
+		for _, assistant := range remote {
+			if matched[assistant.ID] {
+				continue
+			}
+			entry := AssistantSyncResult{
+				Name:        assistant.Name,
+				AssistantID: assistant.ID,
+				Action:      SyncActionDeleted,
+			}
+			if !opts.DryRun {
+				if err := c.DeleteAssistant(ctx, assistant.ID); err != nil {
+					entry.Action = SyncActionFailed
+					entry.Err = err
+				}
+			}
+			result.Results = append(result.Results, entry)
+		}
+	}
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return result, fmt.Errorf("%d of %d assistants failed to sync", len(failed), len(result.Results))
+	}
+
+	return result, nil
+}
+
+// syncAssistantFile creates or updates the assistant defined by a single config file
+func (c *Client) syncAssistantFile(ctx context.Context, file string, remote []Assistant, opts SyncOptions) AssistantSyncResult {
+	entry := AssistantSyncResult{File: file}
+
+	local, err := LoadAssistantConfig(file)
+	if err != nil {
+		entry.Action = SyncActionFailed
+		entry.Err = err
+		return entry
+	}
+
+	entry.Name, _ = local["name"].(string)
+	syncID := assistantSyncID(local)
+	if entry.Name == "" && syncID == "" {
+		entry.Action = SyncActionFailed
+		entry.Err = fmt.Errorf("assistant config %s has neither a name nor a metadata.id", file)
+		return entry
+	}
+
+	existing := findSyncedAssistant(remote, entry.Name, syncID)
+	if existing == nil {
+		entry.Action = SyncActionCreated
+		if !opts.DryRun {
+			created, err := c.CreateAssistant(ctx, local)
+			if err != nil {
+				entry.Action = SyncActionFailed
+				entry.Err = err
+				return entry
+			}
+			entry.AssistantID = created.ID
+		}
+		return entry
+	}
+
+	entry.AssistantID = existing.ID
+	current, err := c.getAssistantConfig(ctx, existing.ID)
+	if err != nil {
+		entry.Action = SyncActionFailed
+		entry.Err = err
+		return entry
+	}
+
+	entry.ChangedFields, err = changedConfigFields(local, current)
+	if err != nil {
+		entry.Action = SyncActionFailed
+		entry.Err = err
+		return entry
+	}
+	if len(entry.ChangedFields) == 0 {
+		entry.Action = SyncActionUnchanged
+		return entry
+	}
+
+	entry.Action = SyncActionUpdated
+	if !opts.DryRun {
+		if _, err := c.PatchAssistant(ctx, existing.ID, local); err != nil {
+			entry.Action = SyncActionFailed
+			entry.Err = err
+		}
+	}
+	return entry
+}
+
+// assistantConfigFiles returns the assistant config files in dir, sorted by name
+func assistantConfigFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read assistant config directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// assistantSyncID returns the metadata.id of an assistant config, if any
+func assistantSyncID(assistantConfig map[string]interface{}) string {
+	metadata, ok := assistantConfig["metadata"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	id, _ := metadata["id"].(string)
+	return id
+}
+
+// findSyncedAssistant finds the assistant matching a config's metadata.id, or its name when it has none
+func findSyncedAssistant(assistants []Assistant, name, syncID string) *Assistant {
+	for i := range assistants {
+		if syncID != "" {
+			if id, _ := assistants[i].Metadata["id"].(string); id == syncID {
+				return &assistants[i]
+			}
+			continue
+		}
+		if assistants[i].Name == name {
+			return &assistants[i]
+		}
+	}
+	return nil
+}
+
+// changedConfigFields returns the top-level fields of local whose values aren't
+// reflected in current. Fields VAPI adds or defaults aren't treated as changes.
+func changedConfigFields(local, current map[string]interface{}) ([]string, error) {
+	// Round-trip through JSON so YAML and API values compare with the same types
+	normalizedLocal, err := normalizeConfig(local)
+	if err != nil {
+		return nil, err
+	}
+	normalizedCurrent, err := normalizeConfig(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for key, value := range normalizedLocal {
+		if !configSubset(value, normalizedCurrent[key]) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+// normalizeConfig round-trips a config through JSON
+func normalizeConfig(assistantConfig map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(assistantConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode assistant config: %w", err)
+	}
+
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, fmt.Errorf("failed to decode assistant config: %w", err)
+	}
+	return normalized, nil
+}
+
+// configSubset returns whether every field set in want has the same value in got
+func configSubset(want, got interface{}) bool {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		gotMap, ok := got.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range want {
+			if !configSubset(value, gotMap[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		gotSlice, ok := got.([]interface{})
+		if !ok || len(gotSlice) != len(want) {
+			return false
+		}
+		for i := range want {
+			if !configSubset(want[i], gotSlice[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return want == got
+	}
+}
@@ -0,0 +1,116 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// ToolFunc implements a function tool. args holds the arguments of the tool
+// call as a JSON object. The returned value is sent back to VAPI as the tool
+// result: strings as-is, anything else JSON-encoded.
+type ToolFunc func(ctx context.Context, args json.RawMessage) (any, error)
+
+// ToolRegistry maps function tool names to the Go functions that execute them
+type ToolRegistry struct {
+	mu    sync.RWMutex
+	tools map[string]ToolFunc
+}
+
+// NewToolRegistry creates an empty tool registry
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{
+		tools: make(map[string]ToolFunc),
+	}
+}
+
+// Register registers fn as the implementation of the function tool name,
+// replacing any earlier registration
+func (r *ToolRegistry) Register(name string, fn ToolFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tools[name] = fn
+}
+
+// lookup returns the function registered for a tool name
+func (r *ToolRegistry) lookup(name string) (ToolFunc, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.tools[name]
+	return fn, ok
+}
+
+// ToolCallResult is the outcome of a single tool call in a tool-calls response
+type ToolCallResult struct {
+	ToolCallID string `json:"toolCallId"`
+	Name       string `json:"name,omitempty"`
+	Result     string `json:"result,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ToolCallsResponse is the webhook response VAPI expects for a tool-calls message
+type ToolCallsResponse struct {
+	Results []ToolCallResult `json:"results"`
+}
+
+// Execute runs every tool call in message and collects the results. Unknown
+// tools and failing calls are reported in the result's Error field rather
+// than failing the whole message.
+func (r *ToolRegistry) Execute(ctx context.Context, message *ToolCallsMessage) *ToolCallsResponse {
+	response := &ToolCallsResponse{
+		Results: make([]ToolCallResult, 0, len(message.ToolCallList)),
+	}
+	for _, call := range message.ToolCallList {
+		response.Results = append(response.Results, r.executeCall(ctx, call))
+	}
+	return response
+}
+
+// executeCall runs a single tool call
+func (r *ToolRegistry) executeCall(ctx context.Context, call ToolCall) ToolCallResult {
+	result := ToolCallResult{
+		ToolCallID: call.ID,
+		Name:       call.Function.Name,
+	}
+
+	fn, ok := r.lookup(call.Function.Name)
+	if !ok {
+		result.Error = fmt.Sprintf("unknown tool %q", call.Function.Name)
+		return result
+	}
+
+	args, err := json.Marshal(call.Function.Arguments)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to encode tool arguments: %v", err)
+		return result
+	}
+	if call.Function.Arguments == nil {
+		args = []byte("{}")
+	}
+
+	value, err := fn(ctx, args)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if text, ok := value.(string); ok {
+		result.Result = text
+		return result
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to encode tool result: %v", err)
+		return result
+	}
+	result.Result = string(encoded)
+	return result
+}
+
+// SetToolRegistry makes the server execute incoming tool-calls messages with
+// registry and respond with their results. A nil registry disables this.
+// Call it before Start; tools can still be registered afterwards.
+func (w *WebhookServer) SetToolRegistry(registry *ToolRegistry) {
+	w.tools = registry
+}
@@ -0,0 +1,152 @@
+package voice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/heirloomz/vapi-go-library/pkg/chat"
+)
+
+// Resource kinds recorded by a ResourceTracker, besides chat.ResourceChat
+// and chat.ResourceSession
+const (
+	ResourceAssistant = "assistant"
+	ResourceTool      = "tool"
+	ResourceFile      = "file"
+	ResourceCall      = "call"
+	// ResourceToolAttachment is a tool attached to an assistant, with an ID
+	// of the form "assistantID/toolID"
+	ResourceToolAttachment = "tool-attachment"
+)
+
+// cleanupOrder is the order Cleanup deletes resources in, so that nothing is
+// deleted while something else still references it. Unknown kinds go last.
+var cleanupOrder = []string{
+	ResourceToolAttachment,
+	chat.ResourceChat,
+	chat.ResourceSession,
+	ResourceCall,
+	ResourceAssistant,
+	ResourceTool,
+	ResourceFile,
+}
+
+// ResourceTracker records the resources created through the clients it's set
+// on, so that tests can delete everything they created with one Cleanup call.
+// Set it with Client.SetResourceTracker and chat.Client.SetResourceTracker.
+type ResourceTracker struct {
+	mu        sync.Mutex
+	resources []trackedResource
+}
+
+// trackedResource is a recorded resource and how to delete it
+type trackedResource struct {
+	kind   string
+	id     string
+	remove func(ctx context.Context) error
+}
+
+// NewResourceTracker creates an empty resource tracker
+func NewResourceTracker() *ResourceTracker {
+	return &ResourceTracker{}
+}
+
+// Record records a resource and the function that deletes it
+func (t *ResourceTracker) Record(kind, id string, remove func(ctx context.Context) error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resources = append(t.resources, trackedResource{kind: kind, id: id, remove: remove})
+}
+
+// Forget drops a resource, e.g. because it was deleted
+func (t *ResourceTracker) Forget(kind, id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, resource := range t.resources {
+		if resource.kind == kind && resource.id == id {
+			t.resources = append(t.resources[:i], t.resources[i+1:]...)
+			return
+		}
+	}
+}
+
+// Resources returns the IDs of the recorded resources by kind
+func (t *ResourceTracker) Resources() map[string][]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	ids := make(map[string][]string)
+	for _, resource := range t.resources {
+		ids[resource.kind] = append(ids[resource.kind], resource.id)
+	}
+	return ids
+}
+
+// Cleanup deletes the recorded resources: tool attachments first, then chats,
+// sessions and calls, then assistants, tools and files. Resources of a kind
+// are deleted newest first. It carries on past failures and returns them
+// joined; resources that couldn't be deleted stay recorded so Cleanup can be
+// called again.
+func (t *ResourceTracker) Cleanup(ctx context.Context) error {
+	t.mu.Lock()
+	resources := append([]trackedResource(nil), t.resources...)
+	t.mu.Unlock()
+
+	rank := make(map[string]int, len(cleanupOrder))
+	for i, kind := range cleanupOrder {
+		rank[kind] = i
+	}
+	kindRank := func(kind string) int {
+		if r, ok := rank[kind]; ok {
+			return r
+		}
+		return len(cleanupOrder)
+	}
+
+	var errs []error
+	for r := 0; r <= len(cleanupOrder); r++ {
+		for i := len(resources) - 1; i >= 0; i-- {
+			resource := resources[i]
+			if kindRank(resource.kind) != r {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			if err := resource.remove(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", resource.kind, resource.id, err))
+				continue
+			}
+			t.Forget(resource.kind, resource.id)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SetResourceTracker records the assistants, tools, files, calls and tool
+// attachments created from now on with tracker. A nil tracker stops recording.
+func (c *Client) SetResourceTracker(tracker *ResourceTracker) {
+	c.resources = tracker
+}
+
+// record records a created resource when a tracker is set
+func (c *Client) record(kind, id string, remove func(ctx context.Context) error) {
+	if c.resources != nil && id != "" {
+		c.resources.Record(kind, id, remove)
+	}
+}
+
+// forget drops a deleted resource from the tracker
+func (c *Client) forget(kind, id string) {
+	if c.resources != nil {
+		c.resources.Forget(kind, id)
+	}
+}
+
+// recordAttachment records a tool attached to an assistant
+func (c *Client) recordAttachment(assistantID, toolID string) {
+	c.record(ResourceToolAttachment, assistantID+"/"+toolID, func(ctx context.Context) error {
+		return c.DetachToolsFromAssistant(ctx, assistantID, []string{toolID})
+	})
+}
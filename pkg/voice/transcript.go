@@ -0,0 +1,161 @@
+package voice
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// NormalizedTranscript returns the call transcript as messages, whichever
+// form VAPI sent it in: analysis transcript, transcript string or messages,
+// conversation, or artifacts
+func (c *Call) NormalizedTranscript() []Message {
+	// Check for transcript in analysis
+	if c.Analysis != nil && c.Analysis.Transcript != nil && len(c.Analysis.Transcript) > 0 {
+		return c.Analysis.Transcript
+	}
+
+	// Check for other transcript sources
+	if c.Transcript != nil {
+		// Check if transcript is a string
+		if transcriptStr, ok := c.Transcript.(string); ok && transcriptStr != "" {
+			return parseTranscriptContent(transcriptStr)
+		}
+
+		// Check if transcript is a slice of messages
+		if transcriptMsgs, ok := c.Transcript.([]Message); ok && len(transcriptMsgs) > 0 {
+			return transcriptMsgs
+		}
+
+		// Calls decoded from JSON hold the messages as generic maps
+		if items, ok := c.Transcript.([]interface{}); ok && len(items) > 0 {
+			if transcriptMsgs := decodeMessages(items); len(transcriptMsgs) > 0 {
+				return transcriptMsgs
+			}
+		}
+	}
+
+	if c.Messages != nil && len(c.Messages) > 0 {
+		return c.Messages
+	}
+
+	if c.Conversation != nil && len(c.Conversation) > 0 {
+		return c.Conversation
+	}
+
+	// Check nested in artifacts
+	if c.Artifacts != nil {
+		for _, artifact := range c.Artifacts {
+			if artifact.Transcript != nil && len(artifact.Transcript) > 0 {
+				return artifact.Transcript
+			}
+
+			if artifact.Content != "" {
+				if strings.Contains(artifact.Content, "Transcript") ||
+					strings.Contains(artifact.Content, "AI") ||
+					strings.Contains(artifact.Content, "User") {
+					return parseTranscriptContent(artifact.Content)
+				}
+			}
+		}
+	}
+
+	return []Message{}
+}
+
+// decodeMessages converts generic JSON message objects into messages
+func decodeMessages(items []interface{}) []Message {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil
+	}
+
+	var messages []Message
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil
+	}
+	return messages
+}
+
+// parseTranscriptContent parses transcript content from a string
+func parseTranscriptContent(content string) []Message {
+	if content == "" {
+		return []Message{}
+	}
+
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	transcript := []Message{}
+
+	currentRole := ""
+	currentText := ""
+
+	// Skip first line if it's just "Transcript"
+	startIdx := 0
+	if len(lines) > 0 && strings.Contains(lines[0], "Transcript") {
+		startIdx = 1
+	}
+
+	for i := startIdx; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		// Skip empty lines
+		if line == "" {
+			continue
+		}
+
+		// Check for new speaker
+		if strings.HasPrefix(line, "AI") || strings.HasPrefix(line, "BOT") || strings.HasPrefix(line, "ASSISTANT") {
+			// Save previous message if exists
+			if currentRole != "" && currentText != "" {
+				transcript = append(transcript, Message{
+					Role: currentRole,
+					Text: strings.TrimSpace(currentText),
+				})
+			}
+
+			currentRole = "assistant"
+			// Extract text after the speaker indicator
+			parts := strings.SplitN(line, " ", 2)
+			if len(parts) > 1 {
+				currentText = strings.TrimSpace(parts[1])
+			} else {
+				currentText = ""
+			}
+		} else if strings.HasPrefix(line, "User") || strings.HasPrefix(line, "USER") || strings.HasPrefix(line, "CLIENT") {
+			// Save previous message if exists
+			if currentRole != "" && currentText != "" {
+				transcript = append(transcript, Message{
+					Role: currentRole,
+					Text: strings.TrimSpace(currentText),
+				})
+			}
+
+			currentRole = "user"
+			// Extract text after the speaker indicator
+			parts := strings.SplitN(line, " ", 2)
+			if len(parts) > 1 {
+				currentText = strings.TrimSpace(parts[1])
+			} else {
+				currentText = ""
+			}
+		} else {
+			// Append to current text if we have a role
+			if currentRole != "" {
+				if currentText != "" {
+					currentText += " " + line
+				} else {
+					currentText = line
+				}
+			}
+		}
+	}
+
+	// Add the last message
+	if currentRole != "" && currentText != "" {
+		transcript = append(transcript, Message{
+			Role: currentRole,
+			Text: strings.TrimSpace(currentText),
+		})
+	}
+
+	return transcript
+}
@@ -0,0 +1,179 @@
+package voice
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// minProgressInterval is the minimum number of bytes between progress callbacks
+const minProgressInterval = 64 * 1024
+
+// ProgressFunc reports upload progress as bytes sent out of total
+type ProgressFunc func(bytesSent, total int64)
+
+// UploadFileWithProgress uploads a file to VAPI, streaming it from disk and
+// calling onProgress as it is sent. onProgress fires roughly every 1% of the
+// file (at least every 64KB) and once more when the upload completes.
+func (c *Client) UploadFileWithProgress(ctx context.Context, filePath string, onProgress ProgressFunc) (*File, error) {
+	fileName := filepath.Base(filePath)
+
+	// Open the file
+	fileHandle, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fileHandle.Close()
+
+	info, err := fileHandle.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// Determine the MIME type
+	mimeType := c.detectMimeType(filePath, fileName)
+
+	return c.uploadReader(ctx, fileName, mimeType, fileHandle, info.Size(), onProgress)
+}
+
+// UploadFileReader uploads the content of r to VAPI as a file called name,
+// without needing it on disk. An empty contentType is derived from the
+// extension of name. The content is streamed with a known length when r is a
+// *bytes.Reader, *bytes.Buffer or *strings.Reader, and chunked otherwise.
+func (c *Client) UploadFileReader(ctx context.Context, name, contentType string, r io.Reader) (*File, error) {
+	if name == "" {
+		return nil, fmt.Errorf("file name is required")
+	}
+	if contentType == "" {
+		// With no path, only the extension of name is used
+		contentType = c.detectMimeType("", name)
+	}
+
+	size := int64(-1)
+	if sized, ok := r.(interface{ Len() int }); ok {
+		size = int64(sized.Len())
+	}
+
+	return c.uploadReader(ctx, name, contentType, r, size, nil)
+}
+
+// uploadReader streams size bytes from r to VAPI as a multipart file upload.
+// A negative size sends the content chunked.
+func (c *Client) uploadReader(ctx context.Context, fileName, mimeType string, r io.Reader, size int64, onProgress ProgressFunc) (*File, error) {
+	// Build the multipart framing around the file content up front so the
+	// content can be streamed with a known Content-Length
+	var framing bytes.Buffer
+	multipartWriter := multipart.NewWriter(&framing)
+
+	// Add the content type field first
+	if err := multipartWriter.WriteField("contentType", mimeType); err != nil {
+		return nil, err
+	}
+
+	// Create a custom form file field with the correct Content-Type
+	h := make(map[string][]string)
+	h["Content-Disposition"] = []string{fmt.Sprintf(`form-data; name="file"; filename="%s"`, fileName)}
+	h["Content-Type"] = []string{mimeType}
+	if _, err := multipartWriter.CreatePart(h); err != nil {
+		return nil, err
+	}
+
+	prefix := append([]byte(nil), framing.Bytes()...)
+	framing.Reset()
+
+	// Close the multipart writer to produce the closing boundary
+	if err := multipartWriter.Close(); err != nil {
+		return nil, err
+	}
+	suffix := append([]byte(nil), framing.Bytes()...)
+
+	content := io.Reader(r)
+	if onProgress != nil {
+		content = newProgressReader(r, size, onProgress)
+	}
+
+	body := io.MultiReader(bytes.NewReader(prefix), content, bytes.NewReader(suffix))
+
+	// Create the request
+	url := fmt.Sprintf("%s/file", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	if size >= 0 {
+		req.ContentLength = int64(len(prefix)) + size + int64(len(suffix))
+	}
+
+	// Set the content type with the boundary
+	req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
+	req.Header.Set("User-Agent", c.config.UserAgent)
+	token, err := c.token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	// Send the request
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, responseError(resp, "failed to upload file")
+	}
+
+	// Parse the response
+	var uploadedFile File
+	if err := c.decodeResponse(resp, &uploadedFile); err != nil {
+		return nil, err
+	}
+
+	c.record(ResourceFile, uploadedFile.ID, func(ctx context.Context) error {
+		return c.DeleteFile(ctx, uploadedFile.ID)
+	})
+	return &uploadedFile, nil
+}
+
+// progressReader counts bytes read and reports progress at intervals
+type progressReader struct {
+	reader     io.Reader
+	total      int64
+	sent       int64
+	lastReport int64
+	interval   int64
+	onProgress ProgressFunc
+}
+
+// newProgressReader wraps r so that onProgress is called as it is read
+func newProgressReader(r io.Reader, total int64, onProgress ProgressFunc) *progressReader {
+	interval := total / 100
+	if interval < minProgressInterval {
+		interval = minProgressInterval
+	}
+	return &progressReader{
+		reader:     r,
+		total:      total,
+		interval:   interval,
+		onProgress: onProgress,
+	}
+}
+
+// Read reads from the underlying reader and reports progress
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	p.sent += int64(n)
+
+	if p.sent-p.lastReport >= p.interval || (err == io.EOF && p.sent != p.lastReport) {
+		p.lastReport = p.sent
+		p.onProgress(p.sent, p.total)
+	}
+
+	return n, err
+}
@@ -1,10 +1,13 @@
 package voice
 
 import (
+	"context"
 	"fmt"
+	"io"
 
 	"github.com/heirloomz/vapi-go-library/pkg/config"
 	"github.com/heirloomz/vapi-go-library/pkg/events"
+	"github.com/heirloomz/vapi-go-library/pkg/httputil"
 )
 
 // VoiceClient provides voice functionality for the VAPI library
@@ -14,6 +17,7 @@ type VoiceClient struct {
 	processor     *CallProcessor
 	eventBus      events.EventBus
 	config        *config.Config
+	failureLog    *RotatingFile
 }
 
 // NewVoiceClient creates a new voice client
@@ -23,9 +27,17 @@ func NewVoiceClient(cfg *config.Config, eventBus events.EventBus) (*VoiceClient,
 		APIToken:   cfg.VAPI.APIToken,
 		BaseURL:    cfg.VAPI.BaseURL,
 		Timeout:    cfg.VAPI.Timeout,
-		StorageDir: "./vapi_storage",
-		CacheDir:   "./vapi_cache",
-		DebugDir:   "./vapi_debug",
+		StorageDir: cfg.Storage.StorageDir,
+		CacheDir:   cfg.Storage.CacheDir,
+		DebugDir:   cfg.Storage.DebugDir,
+		UserAgent:  cfg.VAPI.UserAgent,
+		Transport:  cfg.VAPI.Transport.NewTransport(),
+
+		MaxResponseBytes:    cfg.VAPI.MaxResponseBytes,
+		CaptureRawResponses: cfg.VAPI.CaptureRawResponses,
+
+		TokenProvider:  cfg.VAPI.TokenProvider,
+		HeaderProvider: cfg.VAPI.HeaderProvider,
 	}
 
 	// Create VAPI client
@@ -36,6 +48,25 @@ func NewVoiceClient(cfg *config.Config, eventBus events.EventBus) (*VoiceClient,
 
 	// Create webhook server
 	webhookServer := NewWebhookServer(cfg.Tunnel.Port, eventBus, processor)
+	if cfg.VAPI.Debug {
+		webhookServer.EnablePayloadDump(voiceConfig.DebugDir)
+	}
+	if cfg.Tunnel.MetricsEnabled {
+		webhookServer.EnableMetrics(cfg.Tunnel.MetricsPath)
+	}
+	if err := webhookServer.SetAllowedCIDRs(cfg.Tunnel.AllowedCIDRs, cfg.Tunnel.TrustForwardedFor); err != nil {
+		return nil, err
+	}
+
+	var failureLog *RotatingFile
+	if cfg.Tunnel.FailureLogPath != "" {
+		var err error
+		failureLog, err = NewRotatingFile(cfg.Tunnel.FailureLogPath, cfg.Tunnel.FailureLogMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open webhook failure log: %w", err)
+		}
+		webhookServer.SetFailureLog(failureLog)
+	}
 
 	return &VoiceClient{
 		client:        client,
@@ -43,6 +74,7 @@ func NewVoiceClient(cfg *config.Config, eventBus events.EventBus) (*VoiceClient,
 		processor:     processor,
 		eventBus:      eventBus,
 		config:        cfg,
+		failureLog:    failureLog,
 	}, nil
 }
 
@@ -63,14 +95,50 @@ func (v *VoiceClient) Stop() error {
 		return fmt.Errorf("failed to stop webhook server: %w", err)
 	}
 
+	// Close the VAPI client
+	if err := v.client.Close(); err != nil {
+		return fmt.Errorf("failed to close VAPI client: %w", err)
+	}
+
+	if v.failureLog != nil {
+		if err := v.failureLog.Close(); err != nil {
+			return fmt.Errorf("failed to close webhook failure log: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// SetToolRegistry makes the webhook server execute tool-calls messages with registry
+func (v *VoiceClient) SetToolRegistry(registry *ToolRegistry) {
+	v.webhookServer.SetToolRegistry(registry)
+}
+
+// SetResourceTracker records the resources created from now on with tracker
+func (v *VoiceClient) SetResourceTracker(tracker *ResourceTracker) {
+	v.client.SetResourceTracker(tracker)
+}
+
+// Processor returns the call processor, e.g. to set its PreProcess and PostProcess hooks
+func (v *VoiceClient) Processor() *CallProcessor {
+	return v.processor
+}
+
+// VerifyToken checks that the API token is accepted by VAPI and reports its org
+func (v *VoiceClient) VerifyToken(ctx context.Context) (*TokenInfo, error) {
+	return v.client.VerifyToken(ctx)
+}
+
 // ListAssistants returns a list of VAPI assistants
 func (v *VoiceClient) ListAssistants() ([]Assistant, error) {
 	return v.client.ListAssistants()
 }
 
+// ListFullAssistants returns VAPI assistants with their complete configuration
+func (v *VoiceClient) ListFullAssistants(ctx context.Context, opts *AssistantListOptions) ([]FullAssistant, error) {
+	return v.client.ListFullAssistants(ctx, opts)
+}
+
 // GetAssistant returns a VAPI assistant by ID
 func (v *VoiceClient) GetAssistant(assistantID string) (*Assistant, error) {
 	return v.client.GetAssistant(assistantID)
@@ -81,31 +149,186 @@ func (v *VoiceClient) UpdateAssistant(assistantID string, updateReq *UpdateAssis
 	return v.client.UpdateAssistant(assistantID, updateReq)
 }
 
+// PatchAssistant sends a partial update to a VAPI assistant
+func (v *VoiceClient) PatchAssistant(ctx context.Context, assistantID string, patch map[string]interface{}) (*Assistant, error) {
+	return v.client.PatchAssistant(ctx, assistantID, patch)
+}
+
+// CreateAssistant creates a VAPI assistant from a full assistant config
+func (v *VoiceClient) CreateAssistant(ctx context.Context, assistantConfig map[string]interface{}) (*Assistant, error) {
+	return v.client.CreateAssistant(ctx, assistantConfig)
+}
+
+// DryRunCreateAssistant returns the HTTP request CreateAssistant would send, without sending it
+func (v *VoiceClient) DryRunCreateAssistant(ctx context.Context, assistantConfig map[string]interface{}) (*DryRunRequest, error) {
+	return v.client.DryRunCreateAssistant(ctx, assistantConfig)
+}
+
+// DeleteAssistant deletes a VAPI assistant
+func (v *VoiceClient) DeleteAssistant(ctx context.Context, assistantID string) error {
+	return v.client.DeleteAssistant(ctx, assistantID)
+}
+
+// SyncAssistants reconciles VAPI assistants with the config files in dir
+func (v *VoiceClient) SyncAssistants(ctx context.Context, dir string, opts SyncOptions) (*SyncResult, error) {
+	return v.client.SyncAssistants(ctx, dir, opts)
+}
+
+// BackupAssistant saves a VAPI assistant config to disk
+func (v *VoiceClient) BackupAssistant(ctx context.Context, assistantID, dir string) (string, error) {
+	return v.client.BackupAssistant(ctx, assistantID, dir)
+}
+
+// RestoreAssistant restores a VAPI assistant from a backup file
+func (v *VoiceClient) RestoreAssistant(ctx context.Context, assistantID, file string) (*Assistant, error) {
+	return v.client.RestoreAssistant(ctx, assistantID, file)
+}
+
 // ListCalls returns a list of VAPI calls for an assistant
 func (v *VoiceClient) ListCalls(assistantID string, limit int) ([]Call, error) {
 	return v.client.ListCalls(assistantID, limit)
 }
 
+// EachCall calls fn for every VAPI call matching filter, decoding calls one at a time
+func (v *VoiceClient) EachCall(ctx context.Context, filter *CallFilter, fn func(Call) error) error {
+	return v.client.EachCall(ctx, filter, fn)
+}
+
+// ListCallsByPhoneNumber returns VAPI calls involving a customer or VAPI phone number
+func (v *VoiceClient) ListCallsByPhoneNumber(ctx context.Context, number string, opts *CallLookupOptions) ([]Call, error) {
+	return v.client.ListCallsByPhoneNumber(ctx, number, opts)
+}
+
+// ListScheduledCalls returns VAPI calls that are scheduled but not yet placed
+func (v *VoiceClient) ListScheduledCalls(ctx context.Context, filter *ScheduledCallFilter) ([]Call, error) {
+	return v.client.ListScheduledCalls(ctx, filter)
+}
+
+// CancelScheduledCall cancels a scheduled VAPI call
+func (v *VoiceClient) CancelScheduledCall(ctx context.Context, callID string) error {
+	return v.client.CancelScheduledCall(ctx, callID)
+}
+
+// CreateCall places an outbound VAPI phone call
+func (v *VoiceClient) CreateCall(ctx context.Context, callReq *CreateCallRequest) (*Call, error) {
+	return v.client.CreateCall(ctx, callReq)
+}
+
+// DeleteCall deletes a VAPI call
+func (v *VoiceClient) DeleteCall(ctx context.Context, callID string) error {
+	return v.client.DeleteCall(ctx, callID)
+}
+
+// ValidateCallRequest validates a CreateCallRequest without sending it
+func (v *VoiceClient) ValidateCallRequest(callReq *CreateCallRequest) error {
+	return v.client.ValidateCallRequest(callReq)
+}
+
 // GetCall returns a VAPI call by ID
 func (v *VoiceClient) GetCall(callID string) (*Call, error) {
 	return v.client.GetCall(callID)
 }
 
+// WaitForCall polls a VAPI call until it reaches a terminal status
+func (v *VoiceClient) WaitForCall(ctx context.Context, callID string, opts *WaitOptions) (*Call, error) {
+	return v.client.WaitForCall(ctx, callID, opts)
+}
+
+// ListenToCall streams the live audio of a call from its monitor listen socket
+func (v *VoiceClient) ListenToCall(ctx context.Context, callID string) (io.ReadCloser, error) {
+	return v.client.ListenToCall(ctx, callID)
+}
+
+// GetCallCost returns the cost breakdown of a VAPI call
+func (v *VoiceClient) GetCallCost(ctx context.Context, callID string) (*CostSummary, error) {
+	return v.client.GetCallCost(ctx, callID)
+}
+
 // UploadFile uploads a file to VAPI
 func (v *VoiceClient) UploadFile(filePath string) (*File, error) {
 	return v.client.UploadFile(filePath)
 }
 
+// UploadFileWithProgress uploads a file to VAPI, reporting progress to onProgress
+func (v *VoiceClient) UploadFileWithProgress(ctx context.Context, filePath string, onProgress ProgressFunc) (*File, error) {
+	return v.client.UploadFileWithProgress(ctx, filePath, onProgress)
+}
+
+// UploadFileReader uploads the content of a reader to VAPI as a file called name
+func (v *VoiceClient) UploadFileReader(ctx context.Context, name, contentType string, r io.Reader) (*File, error) {
+	return v.client.UploadFileReader(ctx, name, contentType, r)
+}
+
 // CreateQueryTool creates a query tool for the knowledge base
 func (v *VoiceClient) CreateQueryTool(fileIDs []string, toolName, description string) (*Tool, error) {
 	return v.client.CreateQueryTool(fileIDs, toolName, description)
 }
 
+// CreateKnowledgeBaseFromFiles uploads files and creates a query tool over them
+func (v *VoiceClient) CreateKnowledgeBaseFromFiles(ctx context.Context, paths []string, name, description string) (*Tool, error) {
+	return v.client.CreateKnowledgeBaseFromFiles(ctx, paths, name, description)
+}
+
+// CreateKnowledgeBaseWithOptions uploads files concurrently and creates a query tool over them
+func (v *VoiceClient) CreateKnowledgeBaseWithOptions(ctx context.Context, paths []string, name, description string, opts *KnowledgeBaseOptions) (*KnowledgeBaseResult, error) {
+	return v.client.CreateKnowledgeBaseWithOptions(ctx, paths, name, description, opts)
+}
+
+// GetKnowledgeBaseFiles returns the files of a knowledge base query tool
+func (v *VoiceClient) GetKnowledgeBaseFiles(ctx context.Context, toolID string) ([]File, error) {
+	return v.client.GetKnowledgeBaseFiles(ctx, toolID)
+}
+
+// RefreshKnowledgeBase replaces the files of a knowledge base query tool and deletes the old ones
+func (v *VoiceClient) RefreshKnowledgeBase(ctx context.Context, toolID string, newPaths []string) error {
+	return v.client.RefreshKnowledgeBase(ctx, toolID, newPaths)
+}
+
+// GetTool returns a VAPI tool by ID
+func (v *VoiceClient) GetTool(ctx context.Context, toolID string) (*Tool, error) {
+	return v.client.GetTool(ctx, toolID)
+}
+
+// DeleteTool deletes a VAPI tool
+func (v *VoiceClient) DeleteTool(ctx context.Context, toolID string) error {
+	return v.client.DeleteTool(ctx, toolID)
+}
+
+// GetFile returns a VAPI file by ID
+func (v *VoiceClient) GetFile(ctx context.Context, fileID string) (*File, error) {
+	return v.client.GetFile(ctx, fileID)
+}
+
+// DeleteFile deletes a file from VAPI
+func (v *VoiceClient) DeleteFile(ctx context.Context, fileID string) error {
+	return v.client.DeleteFile(ctx, fileID)
+}
+
 // AttachToolToAssistant attaches a tool to an assistant
 func (v *VoiceClient) AttachToolToAssistant(assistantID, toolID string) error {
 	return v.client.AttachToolToAssistant(assistantID, toolID)
 }
 
+// AttachToolsToAssistant attaches several tools to an assistant in one update
+func (v *VoiceClient) AttachToolsToAssistant(ctx context.Context, assistantID string, toolIDs []string) error {
+	return v.client.AttachToolsToAssistant(ctx, assistantID, toolIDs)
+}
+
+// DetachToolsFromAssistant removes tools from an assistant
+func (v *VoiceClient) DetachToolsFromAssistant(ctx context.Context, assistantID string, toolIDs []string) error {
+	return v.client.DetachToolsFromAssistant(ctx, assistantID, toolIDs)
+}
+
+// LastRawResponse returns the raw body of the most recent VAPI API response when debug mode is enabled
+func (v *VoiceClient) LastRawResponse() []byte {
+	return v.client.LastRawResponse()
+}
+
+// LastRateLimit returns the rate limit state reported by the most recent VAPI response
+func (v *VoiceClient) LastRateLimit() *httputil.RateLimitInfo {
+	return v.client.LastRateLimit()
+}
+
 // ExtractTranscript extracts the transcript from a VAPI call
 func (v *VoiceClient) ExtractTranscript(call *Call) []Message {
 	return v.client.ExtractTranscript(call)
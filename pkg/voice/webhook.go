@@ -1,20 +1,60 @@
 package voice
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/heirloomz/vapi-go-library/pkg/events"
 )
 
+const (
+	// publishTimeout bounds how long webhook processing waits on the event bus
+	publishTimeout = 5 * time.Second
+	// defaultNotFoundRetries is how often a call missing from VAPI is fetched again
+	defaultNotFoundRetries = 2
+	// defaultNotFoundRetryDelay is the base delay before fetching a missing call again
+	defaultNotFoundRetryDelay = time.Second
+)
+
 // WebhookServer handles VAPI webhook events
 type WebhookServer struct {
 	port      int
 	eventBus  events.EventBus
 	processor *CallProcessor
 	server    *http.Server
+	debugDir  string
+
+	// Source address allowlist, see SetAllowedCIDRs
+	allowedNetworks   []*net.IPNet
+	trustForwardedFor bool
+
+	// Cached VAPI token check for the health endpoint
+	tokenCheckMu   sync.Mutex
+	tokenCheckedAt time.Time
+	tokenCheckErr  error
+
+	// Per-event-type counters, see Stats and EnableMetrics
+	stats       webhookStats
+	metricsPath string
+
+	// Function tools executed for tool-calls messages, see SetToolRegistry
+	tools *ToolRegistry
+
+	// Sink for the payloads of failed webhooks, see SetFailureLog
+	failureLogMu sync.Mutex
+	failureLog   io.Writer
 }
 
 // NewWebhookServer creates a new webhook server
@@ -26,14 +66,23 @@ func NewWebhookServer(port int, eventBus events.EventBus, processor *CallProcess
 	}
 }
 
+// EnablePayloadDump writes every received webhook payload, along with its
+// parse and processing results, to dir. An empty dir disables dumping.
+func (w *WebhookServer) EnablePayloadDump(dir string) {
+	w.debugDir = dir
+}
+
 // Start starts the webhook server
 func (w *WebhookServer) Start() error {
 	mux := http.NewServeMux()
 
 	// VAPI webhook endpoint
-	mux.HandleFunc("/webhooks/vapi", w.handleVAPIWebhook)
-	mux.HandleFunc("/webhooks/voice", w.handleVoiceWebhook)
+	mux.HandleFunc("/webhooks/vapi", w.restrictSource(w.handleVAPIWebhook))
+	mux.HandleFunc("/webhooks/voice", w.restrictSource(w.handleVoiceWebhook))
 	mux.HandleFunc("/webhooks/health", w.handleHealthCheck)
+	if w.metricsPath != "" {
+		mux.HandleFunc(w.metricsPath, w.handleMetrics)
+	}
 
 	w.server = &http.Server{
 		Addr:    fmt.Sprintf(":%d", w.port),
@@ -73,14 +122,16 @@ func (w *WebhookServer) handleVAPIWebhook(rw http.ResponseWriter, req *http.Requ
 	}
 
 	// Process the webhook event
-	if err := w.processWebhookEvent(body); err != nil {
+	response, err := w.processWebhookEvent(req.Context(), body)
+	w.dumpPayload(body, err)
+	if err != nil {
+		w.logFailure(body, err)
 		http.Error(rw, "Failed to process webhook event", http.StatusInternalServerError)
 		return
 	}
 
 	// Respond with success
-	rw.WriteHeader(http.StatusOK)
-	rw.Write([]byte("OK"))
+	writeWebhookResponse(rw, response)
 }
 
 // handleVoiceWebhook handles generic voice webhook events
@@ -99,98 +150,420 @@ func (w *WebhookServer) handleVoiceWebhook(rw http.ResponseWriter, req *http.Req
 	}
 
 	// Process the webhook event
-	if err := w.processWebhookEvent(body); err != nil {
+	response, err := w.processWebhookEvent(req.Context(), body)
+	w.dumpPayload(body, err)
+	if err != nil {
+		w.logFailure(body, err)
 		http.Error(rw, "Failed to process webhook event", http.StatusInternalServerError)
 		return
 	}
 
 	// Respond with success
-	rw.WriteHeader(http.StatusOK)
-	rw.Write([]byte("OK"))
+	writeWebhookResponse(rw, response)
 }
 
-// handleHealthCheck handles health check requests
-func (w *WebhookServer) handleHealthCheck(rw http.ResponseWriter, req *http.Request) {
+// writeWebhookResponse responds to VAPI with response as JSON, or a plain OK
+// for messages that don't expect a response
+func writeWebhookResponse(rw http.ResponseWriter, response interface{}) {
+	if response == nil {
+		rw.WriteHeader(http.StatusOK)
+		rw.Write([]byte("OK"))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
 	rw.WriteHeader(http.StatusOK)
-	rw.Write([]byte("OK"))
+	if err := json.NewEncoder(rw).Encode(response); err != nil {
+		log.Printf("voice: failed to write webhook response: %v", err)
+	}
+}
+
+// webhookDump is the debug record written for each received webhook
+type webhookDump struct {
+	ReceivedAt      time.Time       `json:"received_at"`
+	EventType       string          `json:"event_type"`
+	Payload         json.RawMessage `json:"payload,omitempty"`
+	RawBody         string          `json:"raw_body,omitempty"`
+	Parsed          WebhookMessage  `json:"parsed,omitempty"`
+	ParseError      string          `json:"parse_error,omitempty"`
+	ProcessingError string          `json:"processing_error,omitempty"`
+}
+
+// newWebhookDump builds the debug record of a webhook payload and its results
+func newWebhookDump(payload []byte, processErr error) *webhookDump {
+	dump := &webhookDump{
+		ReceivedAt: time.Now(),
+		EventType:  "unknown",
+	}
+
+	if json.Valid(payload) {
+		dump.Payload = payload
+	} else {
+		dump.RawBody = string(payload)
+	}
+
+	message, err := ParseWebhookMessage(payload)
+	if err != nil {
+		dump.ParseError = err.Error()
+	} else {
+		dump.Parsed = message
+		if messageType := message.MessageType(); messageType != "" {
+			dump.EventType = messageType
+		}
+	}
+
+	if processErr != nil {
+		dump.ProcessingError = processErr.Error()
+	}
+	return dump
+}
+
+// dumpPayload writes a webhook payload and its results to the debug directory
+func (w *WebhookServer) dumpPayload(payload []byte, processErr error) {
+	if w.debugDir == "" {
+		return
+	}
+
+	dump := newWebhookDump(payload, processErr)
+	// The type comes from the payload, so keep it safe for use in a file name
+	dump.EventType = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '_'
+	}, dump.EventType)
+
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		log.Printf("voice: failed to encode webhook dump: %v", err)
+		return
+	}
+
+	fileName := fmt.Sprintf("webhook_%s_%s.json", dump.ReceivedAt.Format("20060102T150405.000000"), dump.EventType)
+	if err := os.WriteFile(filepath.Join(w.debugDir, fileName), data, 0644); err != nil {
+		log.Printf("voice: failed to write webhook dump: %v", err)
+	}
 }
 
-// processWebhookEvent processes a webhook event
-func (w *WebhookServer) processWebhookEvent(payload []byte) error {
+// processWebhookEvent processes a webhook event, returning the response body
+// for messages VAPI expects one for, or nil
+func (w *WebhookServer) processWebhookEvent(ctx context.Context, payload []byte) (response interface{}, err error) {
+	var eventType string
+	defer func() {
+		w.stats.record(eventType, err)
+	}()
+
 	// Parse the webhook payload
 	var webhookData map[string]interface{}
 	if err := json.Unmarshal(payload, &webhookData); err != nil {
-		return fmt.Errorf("failed to parse webhook payload: %w", err)
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
 	}
 
 	// Extract the message
-	message, ok := webhookData["message"].(map[string]interface{})
+	rawMessage, ok := webhookData["message"].(map[string]interface{})
 	if !ok {
 		// No message field, skip processing
-		return nil
+		return nil, nil
+	}
+	eventType, _ = rawMessage["type"].(string)
+	if eventType == "" {
+		// Untyped message, skip processing
+		return nil, nil
 	}
 
-	// Check if this is an end-of-call-report event
-	eventType, ok := message["type"].(string)
-	if !ok || eventType != "end-of-call-report" {
-		// Not an end-of-call-report event, skip processing
-		return nil
+	message, err := ParseWebhookMessage(payload)
+	if err != nil {
+		return nil, err
 	}
 
-	// Process the end-of-call-report event
-	if w.processor != nil {
-		return w.processor.ProcessEndOfCallReport(message)
+	// End-of-call-reports are also processed into a call-completed event.
+	// The report itself is only published once that succeeds, since VAPI
+	// retries failed webhooks and would otherwise publish it again.
+	if report, ok := message.(*EndOfCallReport); ok && w.processor != nil {
+		if err := w.processor.ProcessReportContext(ctx, report); err != nil {
+			return nil, err
+		}
 	}
 
-	// Publish raw webhook event to event bus
-	if w.eventBus != nil {
-		event := events.NewEvent(events.EventWebhookReceived, "vapi-webhook", webhookData)
-		return w.eventBus.Publish(event)
+	// VAPI is waiting mid-call on tool results, so tools run first and an
+	// event bus outage only costs the tool-calls event
+	if m, ok := message.(*ToolCallsMessage); ok {
+		if w.tools != nil {
+			response = w.tools.Execute(ctx, m)
+		}
+		if err := w.publishMessage(message, webhookData); err != nil {
+			log.Printf("voice: failed to publish %s event: %v", eventType, err)
+		}
+		return response, nil
 	}
 
-	return nil
+	// Publish every message under the event type for its kind
+	if err := w.publishMessage(message, webhookData); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// messageEventTypes maps VAPI server message types to the event types they're published as
+var messageEventTypes = map[string]string{
+	MessageTypeEndOfCallReport:  events.EventEndOfCallReport,
+	MessageTypeStatusUpdate:     events.EventCallStatusUpdate,
+	MessageTypeTranscript:       events.EventTranscriptUpdate,
+	MessageTypeToolCalls:        events.EventToolCalls,
+	MessageTypeAssistantRequest: events.EventAssistantRequest,
+	MessageTypeHang:             events.EventCallHang,
+	MessageTypeSpeechUpdate:     events.EventSpeechUpdate,
+}
+
+// EventTypeForMessage returns the event type a webhook message of messageType
+// is published as, events.EventWebhookReceived for unknown message types
+func EventTypeForMessage(messageType string) string {
+	if eventType, ok := messageEventTypes[messageType]; ok {
+		return eventType
+	}
+	return events.EventWebhookReceived
+}
+
+// publishMessage publishes a webhook message to the event bus, partitioned by
+// call ID. The typed message is the event data, except for unknown message
+// types, which carry the raw webhook body.
+func (w *WebhookServer) publishMessage(message WebhookMessage, webhookData map[string]interface{}) error {
+	if w.eventBus == nil {
+		return nil
+	}
+
+	var data interface{} = message
+	if _, unknown := message.(*UnknownWebhookMessage); unknown {
+		data = webhookData
+	}
+
+	event := events.NewEvent(EventTypeForMessage(message.MessageType()), "vapi-webhook", data)
+	event.PartitionKey = messageCallID(message)
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	return w.eventBus.PublishContext(ctx, event)
+}
+
+// messageCallID returns the ID of the call a webhook message belongs to, if known
+func messageCallID(message WebhookMessage) string {
+	var call *Call
+	switch m := message.(type) {
+	case *EndOfCallReport:
+		return m.GetCallID()
+	case *StatusUpdateMessage:
+		call = m.Call
+	case *TranscriptMessage:
+		call = m.Call
+	case *ToolCallsMessage:
+		call = m.Call
+	case *AssistantRequestMessage:
+		call = m.Call
+	case *HangMessage:
+		call = m.Call
+	case *SpeechUpdateMessage:
+		call = m.Call
+	}
+	if call == nil {
+		return ""
+	}
+	return call.ID
 }
 
 // CallProcessor handles processing of call events
 type CallProcessor struct {
 	client   *Client
 	eventBus events.EventBus
+
+	// PreProcess, when set, runs on the fetched call before it's processed.
+	// Returning an error aborts processing.
+	PreProcess func(*Call) error
+	// PreferReportPayload builds the processed call from the end-of-call-report
+	// itself, which already holds the call, transcript and analysis, and only
+	// fetches the call from VAPI when the report has no transcript. This
+	// avoids an API round trip that can 404 while the call record lags the
+	// webhook, and lets the processor run without a client.
+	PreferReportPayload bool
+
+	// NotFoundRetries is how often fetching the call is retried when VAPI
+	// returns 404, which happens while the call record lags the webhook.
+	// Retries wait NotFoundRetryDelay, doubling each time, with jitter.
+	NotFoundRetries    int
+	NotFoundRetryDelay time.Duration
+
+	// PostProcess, when set, runs on the processed call before the
+	// call-completed event is published, e.g. to redact PII. Returning an
+	// error aborts processing and nothing is published.
+	PostProcess func(*ProcessedCall) error
 }
 
-// NewCallProcessor creates a new call processor
+// NewCallProcessor creates a new call processor. client may be nil when
+// PreferReportPayload is set, in which case incomplete reports fail.
 func NewCallProcessor(client *Client, eventBus events.EventBus) *CallProcessor {
 	return &CallProcessor{
-		client:   client,
-		eventBus: eventBus,
+		client:             client,
+		eventBus:           eventBus,
+		NotFoundRetries:    defaultNotFoundRetries,
+		NotFoundRetryDelay: defaultNotFoundRetryDelay,
+	}
+}
+
+// resolveCall returns the call for a report, taken from the report itself when
+// PreferReportPayload is set and it's complete, and fetched from VAPI otherwise
+func (p *CallProcessor) resolveCall(ctx context.Context, report *EndOfCallReport) (*Call, error) {
+	if p.PreferReportPayload {
+		if call, ok := callFromReport(report); ok {
+			return call, nil
+		}
+	}
+
+	if p.client == nil {
+		return nil, fmt.Errorf("end-of-call-report for call %s is incomplete and no client is configured to fetch it", report.GetCallID())
+	}
+
+	// Get full call details from VAPI API
+	call, err := p.fetchCall(ctx, report.GetCallID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get call details: %w", err)
+	}
+	return call, nil
+}
+
+// fetchCall gets a call from VAPI, retrying with backoff while it's not found,
+// until ctx is done
+func (p *CallProcessor) fetchCall(ctx context.Context, callID string) (*Call, error) {
+	delay := p.NotFoundRetryDelay
+	for attempt := 0; ; attempt++ {
+		call, err := p.client.GetCallContext(ctx, callID)
+		if err == nil || !errors.Is(err, ErrCallNotFound) || attempt >= p.NotFoundRetries {
+			return call, err
+		}
+
+		// Wait between half and the full delay so concurrent retries spread out
+		wait := delay
+		if delay > 0 {
+			wait = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		}
+		log.Printf("voice: call %s not found yet, retrying in %s", callID, wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+		delay *= 2
+	}
+}
+
+// callFromReport builds a call from the fields of an end-of-call-report,
+// reporting false when the report has no transcript
+func callFromReport(report *EndOfCallReport) (*Call, bool) {
+	call := report.Call
+	if call.ID == "" {
+		call.ID = report.CallID
+	}
+	if call.AssistantID == "" {
+		call.AssistantID = report.AssistantID
+	}
+	if call.Transcript == nil {
+		call.Transcript = report.Transcript
+	}
+	if len(call.Messages) == 0 {
+		call.Messages = report.Messages
+	}
+	if call.Analysis == nil {
+		call.Analysis = report.Analysis
+	}
+	if call.EndedReason == "" {
+		call.EndedReason = report.EndedReason
+	}
+	if call.Cost == 0 {
+		call.Cost = report.Cost
+	}
+	if call.Duration == 0 {
+		call.Duration = int(report.DurationSecs)
+	}
+	if call.Summary == "" {
+		call.Summary = report.Summary
+	}
+	if call.RecordingURL == "" {
+		call.RecordingURL = report.RecordingURL
+	}
+	if call.Status == "" {
+		// The report is only sent once the call has ended
+		call.Status = CallStatusEnded
+	}
+
+	if len(call.NormalizedTranscript()) == 0 {
+		return nil, false
 	}
+	return &call, true
+}
+
+// DecodeEndOfCallReport decodes a raw end-of-call-report message into its typed form
+func DecodeEndOfCallReport(message map[string]interface{}) (*EndOfCallReport, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode end-of-call-report: %w", err)
+	}
+
+	var report EndOfCallReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode end-of-call-report: %w", err)
+	}
+
+	return &report, nil
 }
 
 // ProcessEndOfCallReport processes an end-of-call-report event
 func (p *CallProcessor) ProcessEndOfCallReport(message map[string]interface{}) error {
-	// Extract call information
-	callData, ok := message["call"].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("no call data in end-of-call-report")
+	return p.ProcessEndOfCallReportContext(context.Background(), message)
+}
+
+// ProcessEndOfCallReportContext processes an end-of-call-report event,
+// honoring ctx cancellation
+func (p *CallProcessor) ProcessEndOfCallReportContext(ctx context.Context, message map[string]interface{}) error {
+	report, err := DecodeEndOfCallReport(message)
+	if err != nil {
+		return err
 	}
 
-	callID, ok := callData["id"].(string)
-	if !ok {
+	return p.ProcessReportContext(ctx, report)
+}
+
+// ProcessReport processes a typed end-of-call-report
+func (p *CallProcessor) ProcessReport(report *EndOfCallReport) error {
+	return p.ProcessReportContext(context.Background(), report)
+}
+
+// ProcessReportContext processes a typed end-of-call-report, honoring ctx
+// cancellation while the call is fetched from VAPI
+func (p *CallProcessor) ProcessReportContext(ctx context.Context, report *EndOfCallReport) error {
+	callID := report.GetCallID()
+	if callID == "" {
 		return fmt.Errorf("no call ID in end-of-call-report")
 	}
 
-	assistantID, ok := callData["assistantId"].(string)
-	if !ok {
+	assistantID := report.GetAssistantID()
+	if assistantID == "" {
 		return fmt.Errorf("no assistant ID in end-of-call-report")
 	}
 
-	// Get full call details from VAPI API
-	call, err := p.client.GetCall(callID)
+	call, err := p.resolveCall(ctx, report)
 	if err != nil {
-		return fmt.Errorf("failed to get call details: %w", err)
+		return err
+	}
+
+	if p.PreProcess != nil {
+		if err := p.PreProcess(call); err != nil {
+			return fmt.Errorf("pre-processing failed for call %s: %w", callID, err)
+		}
 	}
 
 	// Extract transcript
-	transcript := p.client.ExtractTranscript(call)
+	transcript := call.NormalizedTranscript()
 
 	// Create processed call
 	processedCall := &ProcessedCall{
@@ -200,14 +573,57 @@ func (p *CallProcessor) ProcessEndOfCallReport(message map[string]interface{}) e
 		Transcript:  transcript,
 		Duration:    call.Duration,
 		Status:      call.Status,
+		EndedReason: call.EndedReason,
+		Metadata:    call.Metadata,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
+	// Include the analysis summary and structured data when available
+	if call.Analysis != nil {
+		processedCall.Summary = call.Analysis.Summary
+		processedCall.StructuredData = call.Analysis.StructuredData
+	}
+
+	// The webhook carries the ended reason even when the fetched call doesn't
+	if processedCall.EndedReason == "" {
+		processedCall.EndedReason = report.EndedReason
+	}
+	if processedCall.Metadata == nil {
+		processedCall.Metadata = report.Call.Metadata
+	}
+
+	// Fall back to the report's own summary and analysis
+	if processedCall.Summary == "" {
+		processedCall.Summary = report.Summary
+	}
+	if report.Analysis != nil {
+		if processedCall.Summary == "" {
+			processedCall.Summary = report.Analysis.Summary
+		}
+		if processedCall.StructuredData == nil {
+			processedCall.StructuredData = report.Analysis.StructuredData
+		}
+	}
+
+	if p.PostProcess != nil {
+		if err := p.PostProcess(processedCall); err != nil {
+			return fmt.Errorf("post-processing failed for call %s: %w", callID, err)
+		}
+	}
+
 	// Publish call-completed event
 	if p.eventBus != nil {
-		event := events.NewEvent(events.EventCallCompleted, "vapi-processor", processedCall)
-		if err := p.eventBus.Publish(event); err != nil {
+		cost := call.Cost
+		if cost == 0 {
+			cost = report.Cost
+		}
+		payload := NewCallCompletedPayload(processedCall, cost)
+		event := events.NewEvent(events.EventCallCompleted, "vapi-processor", payload)
+		event.PartitionKey = callID
+		publishCtx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		defer cancel()
+		if err := p.eventBus.PublishContext(publishCtx, event); err != nil {
 			return fmt.Errorf("failed to publish call-completed event: %w", err)
 		}
 	}
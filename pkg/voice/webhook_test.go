@@ -0,0 +1,370 @@
+package voice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/heirloomz/vapi-go-library/pkg/events"
+)
+
+const (
+	fixtureCallID       = "c0a8012e-4f3b-4a7e-9d4c-6f0e2b1d9a11"
+	fixtureAssistantID  = "4b7e6a52-1c0d-4e8f-a9b3-7d6c5e4f3a21"
+	fixtureRecordingURL = "https://storage.vapi.ai/c0a8012e-4f3b-4a7e-9d4c-6f0e2b1d9a11-1760611200000-mono.wav"
+	fixtureSummary      = "The assistant called to confirm a dental cleaning tomorrow at 3pm and the customer confirmed."
+	fixtureCost         = 0.0871
+)
+
+// recordingBus is an event bus that keeps every published event
+type recordingBus struct {
+	*events.NoopEventBus
+
+	mu        sync.Mutex
+	published []*events.Event
+}
+
+func newRecordingBus() *recordingBus {
+	return &recordingBus{NoopEventBus: events.NewNoopEventBus()}
+}
+
+func (b *recordingBus) Publish(event *events.Event) error {
+	return b.PublishContext(context.Background(), event)
+}
+
+func (b *recordingBus) PublishContext(ctx context.Context, event *events.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = append(b.published, event)
+	return nil
+}
+
+func (b *recordingBus) eventsOfType(eventType string) []*events.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var matched []*events.Event
+	for _, event := range b.published {
+		if event.Type == eventType {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// loadEndOfCallReport reads the end-of-call-report fixture, replacing its
+// ended reason when endedReason is set
+func loadEndOfCallReport(t *testing.T, endedReason string) []byte {
+	t.Helper()
+
+	payload, err := os.ReadFile("testdata/end-of-call-report.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if endedReason == "" {
+		return payload
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(payload, &body); err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	body["message"].(map[string]interface{})["endedReason"] = endedReason
+	payload, err = json.Marshal(body)
+	if err != nil {
+		t.Fatalf("failed to encode fixture: %v", err)
+	}
+	return payload
+}
+
+func TestParseWebhookMessageEndOfCallReport(t *testing.T) {
+	message, err := ParseWebhookMessage(loadEndOfCallReport(t, ""))
+	if err != nil {
+		t.Fatalf("ParseWebhookMessage: %v", err)
+	}
+
+	report, ok := message.(*EndOfCallReport)
+	if !ok {
+		t.Fatalf("message is %T, want *EndOfCallReport", message)
+	}
+
+	if got := report.GetCallID(); got != fixtureCallID {
+		t.Errorf("call ID = %q, want %q", got, fixtureCallID)
+	}
+	if got := report.GetAssistantID(); got != fixtureAssistantID {
+		t.Errorf("assistant ID = %q, want %q", got, fixtureAssistantID)
+	}
+	if report.EndedReason != EndedReasonCustomerEndedCall {
+		t.Errorf("EndedReason = %q, want %q", report.EndedReason, EndedReasonCustomerEndedCall)
+	}
+	if report.RecordingURL != fixtureRecordingURL {
+		t.Errorf("RecordingURL = %q, want %q", report.RecordingURL, fixtureRecordingURL)
+	}
+	if report.Cost != fixtureCost {
+		t.Errorf("Cost = %v, want %v", report.Cost, fixtureCost)
+	}
+	if report.Summary != fixtureSummary {
+		t.Errorf("Summary = %q, want %q", report.Summary, fixtureSummary)
+	}
+
+	if report.Analysis == nil {
+		t.Fatal("Analysis is nil")
+	}
+	if report.Analysis.Summary != fixtureSummary {
+		t.Errorf("Analysis.Summary = %q, want %q", report.Analysis.Summary, fixtureSummary)
+	}
+	if confirmed, _ := report.Analysis.StructuredData["confirmed"].(bool); !confirmed {
+		t.Errorf("Analysis.StructuredData = %v, want confirmed=true", report.Analysis.StructuredData)
+	}
+	if report.Analysis.SuccessEvaluation == nil {
+		t.Fatal("Analysis.SuccessEvaluation is nil")
+	}
+	if passed, err := report.Analysis.SuccessEvaluation.Passed(); err != nil || !passed {
+		t.Errorf("SuccessEvaluation.Passed() = %v, %v, want true, nil", passed, err)
+	}
+}
+
+func TestProcessReportEndedReasons(t *testing.T) {
+	tests := []struct {
+		name        string
+		endedReason string
+	}{
+		{name: "hangup", endedReason: EndedReasonCustomerEndedCall},
+		{name: "voicemail", endedReason: EndedReasonVoicemail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := ParseWebhookMessage(loadEndOfCallReport(t, tt.endedReason))
+			if err != nil {
+				t.Fatalf("ParseWebhookMessage: %v", err)
+			}
+			report := message.(*EndOfCallReport)
+
+			bus := newRecordingBus()
+			processor := NewCallProcessor(nil, bus)
+			processor.PreferReportPayload = true
+
+			var processed *ProcessedCall
+			processor.PostProcess = func(call *ProcessedCall) error {
+				processed = call
+				return nil
+			}
+
+			if err := processor.ProcessReport(report); err != nil {
+				t.Fatalf("ProcessReport: %v", err)
+			}
+
+			if processed == nil {
+				t.Fatal("PostProcess was not called")
+			}
+			if processed.EndedReason != tt.endedReason {
+				t.Errorf("EndedReason = %q, want %q", processed.EndedReason, tt.endedReason)
+			}
+			if processed.Summary != fixtureSummary {
+				t.Errorf("Summary = %q, want %q", processed.Summary, fixtureSummary)
+			}
+			if confirmed, _ := processed.StructuredData["confirmed"].(bool); !confirmed {
+				t.Errorf("StructuredData = %v, want confirmed=true", processed.StructuredData)
+			}
+			if len(processed.Transcript) != 3 {
+				t.Errorf("transcript has %d messages, want 3", len(processed.Transcript))
+			}
+
+			completed := bus.eventsOfType(events.EventCallCompleted)
+			if len(completed) != 1 {
+				t.Fatalf("published %d call-completed events, want 1", len(completed))
+			}
+			payload, ok := completed[0].Data.(*CallCompletedPayload)
+			if !ok {
+				t.Fatalf("call-completed data is %T, want *CallCompletedPayload", completed[0].Data)
+			}
+			if payload.CallID != fixtureCallID {
+				t.Errorf("payload CallID = %q, want %q", payload.CallID, fixtureCallID)
+			}
+			if payload.EndedReason != tt.endedReason {
+				t.Errorf("payload EndedReason = %q, want %q", payload.EndedReason, tt.endedReason)
+			}
+			if payload.Cost != fixtureCost {
+				t.Errorf("payload Cost = %v, want %v", payload.Cost, fixtureCost)
+			}
+		})
+	}
+}
+
+func TestCallFromReportKeepsRecordingURL(t *testing.T) {
+	message, err := ParseWebhookMessage(loadEndOfCallReport(t, ""))
+	if err != nil {
+		t.Fatalf("ParseWebhookMessage: %v", err)
+	}
+
+	call, ok := callFromReport(message.(*EndOfCallReport))
+	if !ok {
+		t.Fatal("callFromReport reported an incomplete report")
+	}
+	if call.RecordingURL != fixtureRecordingURL {
+		t.Errorf("RecordingURL = %q, want %q", call.RecordingURL, fixtureRecordingURL)
+	}
+	if call.Cost != fixtureCost {
+		t.Errorf("Cost = %v, want %v", call.Cost, fixtureCost)
+	}
+	if call.Status != CallStatusEnded {
+		t.Errorf("Status = %q, want %q", call.Status, CallStatusEnded)
+	}
+}
+
+func TestWebhookStatsBucketsUnknownTypes(t *testing.T) {
+	var stats webhookStats
+	stats.record(MessageTypeStatusUpdate, nil)
+	stats.record("", nil)
+	for i := 0; i < 100; i++ {
+		stats.record(fmt.Sprintf("made-up-type-%d", i), nil)
+	}
+
+	snapshot := stats.snapshot()
+	if len(snapshot) != 2 {
+		t.Errorf("stats have %d keys, want 2: %v", len(snapshot), snapshot)
+	}
+	if got := snapshot[unknownEventType].Received; got != 101 {
+		t.Errorf("unknown received = %d, want 101", got)
+	}
+	if got := snapshot[MessageTypeStatusUpdate].Received; got != 1 {
+		t.Errorf("%s received = %d, want 1", MessageTypeStatusUpdate, got)
+	}
+}
+
+func TestHandleMetricsExportsKnownTypesOnly(t *testing.T) {
+	server := NewWebhookServer(0, nil, nil)
+	server.stats.record(MessageTypeTranscript, nil)
+	server.stats.record(`injected"} 1`+"\nvapi_fake_total{type=\"x", nil)
+
+	rec := httptest.NewRecorder()
+	server.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/webhooks/metrics", nil))
+	body := rec.Body.String()
+
+	if strings.Contains(body, "injected") || strings.Contains(body, "vapi_fake_total") {
+		t.Errorf("metrics contain a label from the payload:\n%s", body)
+	}
+	for _, want := range []string{
+		`vapi_webhook_events_received_total{type="transcript"} 1`,
+		`vapi_webhook_events_received_total{type="unknown"} 1`,
+		`vapi_webhook_events_received_total{type="end-of-call-report"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+
+	series := strings.Count(body, "vapi_webhook_events_received_total{")
+	if want := len(messageEventTypes) + 1; series != want {
+		t.Errorf("received counter has %d series, want %d", series, want)
+	}
+}
+
+func TestEndOfCallReportPublishedAfterProcessing(t *testing.T) {
+	tests := []struct {
+		name        string
+		processErr  error
+		wantReports int
+	}{
+		{name: "processing succeeds", wantReports: 1},
+		{name: "processing fails", processErr: fmt.Errorf("database unavailable"), wantReports: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := newRecordingBus()
+			processor := NewCallProcessor(nil, bus)
+			processor.PreferReportPayload = true
+			processor.PostProcess = func(*ProcessedCall) error {
+				return tt.processErr
+			}
+			server := NewWebhookServer(0, bus, processor)
+
+			_, err := server.processWebhookEvent(context.Background(), loadEndOfCallReport(t, ""))
+			if (err != nil) != (tt.processErr != nil) {
+				t.Fatalf("processWebhookEvent error = %v, want error %v", err, tt.processErr != nil)
+			}
+
+			if got := len(bus.eventsOfType(events.EventEndOfCallReport)); got != tt.wantReports {
+				t.Errorf("published %d end-of-call-report events, want %d", got, tt.wantReports)
+			}
+		})
+	}
+}
+
+// failingBus is an event bus whose publishes always fail
+type failingBus struct {
+	*events.NoopEventBus
+}
+
+func (b *failingBus) Publish(event *events.Event) error {
+	return b.PublishContext(context.Background(), event)
+}
+
+func (b *failingBus) PublishContext(ctx context.Context, event *events.Event) error {
+	return fmt.Errorf("redis unavailable")
+}
+
+func TestToolCallsExecutedWhenPublishFails(t *testing.T) {
+	tools := NewToolRegistry()
+	tools.Register("lookup_order", func(ctx context.Context, args json.RawMessage) (any, error) {
+		return "shipped", nil
+	})
+
+	server := NewWebhookServer(0, &failingBus{NoopEventBus: events.NewNoopEventBus()}, nil)
+	server.SetToolRegistry(tools)
+
+	payload := `{"message":{"type":"tool-calls","toolCallList":[{"id":"call_1","type":"function","function":{"name":"lookup_order","arguments":{"orderId":"42"}}}]}}`
+	response, err := server.processWebhookEvent(context.Background(), []byte(payload))
+	if err != nil {
+		t.Fatalf("processWebhookEvent: %v", err)
+	}
+
+	results, ok := response.(*ToolCallsResponse)
+	if !ok {
+		t.Fatalf("response is %T, want *ToolCallsResponse", response)
+	}
+	if len(results.Results) != 1 || results.Results[0].Result != "shipped" {
+		t.Errorf("results = %+v, want one shipped result", results.Results)
+	}
+}
+
+func TestFetchCallStopsRetryingWhenContextDone(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"Call not found"}`)
+	}))
+	defer server.Close()
+
+	processor := NewCallProcessor(NewClient(&Config{APIToken: "test-token", BaseURL: server.URL}), nil)
+	processor.NotFoundRetries = 5
+	processor.NotFoundRetryDelay = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := processor.fetchCall(ctx, fixtureCallID)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("fetchCall error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("fetchCall took %s after the context expired", elapsed)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("made %d requests, want 1", got)
+	}
+}
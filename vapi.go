@@ -1,7 +1,10 @@
 package vapi
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/heirloomz/vapi-go-library/pkg/chat"
 	"github.com/heirloomz/vapi-go-library/pkg/config"
@@ -9,6 +12,9 @@ import (
 	"github.com/heirloomz/vapi-go-library/pkg/voice"
 )
 
+// Version is the library version
+const Version = config.Version
+
 // Library represents the main VAPI library
 type Library struct {
 	config      *config.Config
@@ -25,11 +31,17 @@ func New(cfg *config.Config) (*Library, error) {
 	}
 
 	// Initialize event bus
+	handlerRetries, retryDelay := handlerRetrySettings(cfg.Workers)
 	eventBus, err := events.NewEventBus(cfg.Events.Backend, events.RedisConfig{
 		Host:     cfg.Events.Redis.Host,
 		Port:     cfg.Events.Redis.Port,
 		Password: cfg.Events.Redis.Password,
 		DB:       cfg.Events.Redis.DB,
+
+		OrderedDelivery: cfg.Events.OrderedDelivery,
+
+		HandlerRetries:    handlerRetries,
+		HandlerRetryDelay: retryDelay,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create event bus: %w", err)
@@ -53,19 +65,51 @@ func New(cfg *config.Config) (*Library, error) {
 	}, nil
 }
 
+// handlerRetrySettings returns the event handler retry settings to run with.
+// Negative retries disable retrying and a delay that isn't positive gets the
+// default, since configs built by hand skip the config package's defaults.
+func handlerRetrySettings(workers config.WorkersConfig) (int, time.Duration) {
+	retries, delay := workers.HandlerRetries, workers.RetryDelay
+	if retries < 0 {
+		log.Printf("vapi: ignoring negative handler retries %d", retries)
+		retries = 0
+	}
+	if delay <= 0 {
+		if delay < 0 {
+			log.Printf("vapi: ignoring negative retry delay %s", delay)
+		}
+		delay = config.DefaultWorkerRetryDelay
+	}
+
+	log.Printf("vapi: event handler retries=%d retry_delay=%s", retries, delay)
+	return retries, delay
+}
+
 // Start starts the VAPI library services
 func (l *Library) Start() error {
+	return l.StartContext(context.Background())
+}
+
+// StartContext starts the VAPI library services, giving up when ctx is done.
+// Services that already started are stopped again if a later one fails or
+// ctx is done first.
+func (l *Library) StartContext(ctx context.Context) error {
 	if l.running {
 		return fmt.Errorf("library is already running")
 	}
 
-	// Start event bus
-	if err := l.eventBus.Start(); err != nil {
+	// Start event bus, letting it watch ctx itself when it can so a slow
+	// connect is abandoned rather than left running
+	startBus := l.eventBus.Start
+	if starter, ok := l.eventBus.(events.ContextStarter); ok {
+		startBus = func() error { return starter.StartContext(ctx) }
+	}
+	if err := startWithContext(ctx, startBus, l.eventBus.Stop); err != nil {
 		return fmt.Errorf("failed to start event bus: %w", err)
 	}
 
 	// Start voice client
-	if err := l.voiceClient.Start(); err != nil {
+	if err := startWithContext(ctx, l.voiceClient.Start, l.voiceClient.Stop); err != nil {
 		l.eventBus.Stop() // Clean up event bus on failure
 		return fmt.Errorf("failed to start voice client: %w", err)
 	}
@@ -74,6 +118,31 @@ func (l *Library) Start() error {
 	return nil
 }
 
+// startWithContext runs start, returning ctx's error if ctx is done first. A
+// start that completes after ctx is done is undone with stop.
+func startWithContext(ctx context.Context, start, stop func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- start()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		go func() {
+			if err := <-done; err == nil {
+				stop()
+			}
+		}()
+		return ctx.Err()
+	}
+}
+
 // Stop stops the VAPI library services
 func (l *Library) Stop() error {
 	if !l.running {
@@ -114,6 +183,17 @@ func (l *Library) Voice() *voice.VoiceClient {
 	return l.voiceClient
 }
 
+// SetResourceTracker records everything the voice and chat clients create
+// from now on with tracker, so it can all be deleted with tracker.Cleanup
+func (l *Library) SetResourceTracker(tracker *voice.ResourceTracker) {
+	l.voiceClient.SetResourceTracker(tracker)
+	if tracker == nil {
+		l.chatClient.SetResourceTracker(nil)
+		return
+	}
+	l.chatClient.SetResourceTracker(tracker)
+}
+
 // Config returns the library configuration
 func (l *Library) Config() *config.Config {
 	return l.config
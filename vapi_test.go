@@ -0,0 +1,31 @@
+package vapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/heirloomz/vapi-go-library/pkg/config"
+)
+
+func TestHandlerRetrySettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		workers     config.WorkersConfig
+		wantRetries int
+		wantDelay   time.Duration
+	}{
+		{name: "unset", wantRetries: 0, wantDelay: config.DefaultWorkerRetryDelay},
+		{name: "configured", workers: config.WorkersConfig{HandlerRetries: 2, RetryDelay: time.Second}, wantRetries: 2, wantDelay: time.Second},
+		{name: "negative retries", workers: config.WorkersConfig{HandlerRetries: -1, RetryDelay: time.Second}, wantRetries: 0, wantDelay: time.Second},
+		{name: "negative delay", workers: config.WorkersConfig{HandlerRetries: 3, RetryDelay: -time.Second}, wantRetries: 3, wantDelay: config.DefaultWorkerRetryDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retries, delay := handlerRetrySettings(tt.workers)
+			if retries != tt.wantRetries || delay != tt.wantDelay {
+				t.Errorf("handlerRetrySettings() = %d, %s, want %d, %s", retries, delay, tt.wantRetries, tt.wantDelay)
+			}
+		})
+	}
+}